@@ -10,6 +10,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -17,6 +19,8 @@ import (
 
 	"github.com/joho/godotenv"
 
+	"gomora/infrastructures/database"
+	"gomora/interfaces"
 	"gomora/interfaces/http/grpc"
 	"gomora/interfaces/http/rest"
 )
@@ -29,6 +33,15 @@ func init() {
 }
 
 func main() {
+	// run the schema migrations instead of the servers
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatalf("[MIGRATE] %v", err)
+		}
+
+		return
+	}
+
 	// grpc port
 	grpcPort, err := strconv.Atoi(os.Getenv("API_URL_GRPC_PORT"))
 	if err != nil {
@@ -53,3 +66,64 @@ func main() {
 	// serve grpc server
 	grpc.GRPCServer().Serve(grpcPort)
 }
+
+// runMigrate handles: migrate up [N] | down [N] | goto V | version | force V
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up [N] | down [N] | goto V | version | force V")
+	}
+
+	db, migrator, err := interfaces.ConnectDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// optional numeric argument
+	n := 0
+	if len(args) > 1 {
+		if n, err = strconv.Atoi(args[1]); err != nil {
+			return fmt.Errorf("invalid number %q", args[1])
+		}
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		err = migrator.Up(ctx, n)
+	case "down":
+		err = migrator.Down(ctx, n)
+	case "goto", "force":
+		if len(args) < 2 {
+			return fmt.Errorf("%s requires a version", args[0])
+		}
+		if args[0] == "goto" {
+			err = migrator.Goto(ctx, n)
+		} else {
+			err = migrator.Force(ctx, n)
+		}
+	case "version":
+		version, dirty, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d (dirty: %t)\n", version, dirty)
+
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+
+	if errors.Is(err, database.ErrNoChange) {
+		fmt.Println("[MIGRATE] no change")
+
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("[MIGRATE] done")
+
+	return nil
+}
@@ -13,8 +13,10 @@ func (c *Config) AllowedHeaders() []string {
 	return []string{
 		"Accept",
 		"Authorization",
+		"X-API-Key",
 		"Content-Type",
 		"X-CSRF-Token",
+		"X-Tenant-ID",
 	}
 }
 
@@ -37,7 +39,13 @@ func (c *Config) AllowedMethods() []string {
 
 // ExposedHeaders returns list of exposed headers
 func (c *Config) ExposedHeaders() []string {
-	return []string{"Link"}
+	return []string{
+		"Link",
+		"RateLimit-Limit",
+		"RateLimit-Remaining",
+		"RateLimit-Reset",
+		"Retry-After",
+	}
 }
 
 // MaxAge returns the maximum number of age in browser in seconds
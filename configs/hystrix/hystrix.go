@@ -1,15 +1,148 @@
 package hystrix
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/afex/hystrix-go/hystrix"
 )
 
+// the circuit breaker commands
+const (
+	InsertRecord     = "insert_record"
+	SelectRecordByID = "select_record_by_id"
+)
+
 // Config handles the hystrix configurations
+// The defaults are overridden by the json file at HYSTRIX_CONFIG, keyed by command name, then by the env
+// like HYSTRIX_SELECT_RECORD_BY_ID_TIMEOUT=500ms. The unset settings keep the hystrix defaults.
 type Config struct{}
 
-// Settings returns the hystrix command config
-func (c Config) Settings() hystrix.CommandConfig {
-	return hystrix.CommandConfig{
-		Timeout: 3000,
+// envSettings maps the env suffixes to the settings they override
+var envSettings = map[string]func(config *hystrix.CommandConfig, value string) error{
+	"TIMEOUT": func(config *hystrix.CommandConfig, value string) error {
+		return parseMilliseconds(&config.Timeout, value)
+	},
+	"MAX_CONCURRENT_REQUESTS": func(config *hystrix.CommandConfig, value string) error {
+		return parseInt(&config.MaxConcurrentRequests, value)
+	},
+	"ERROR_PERCENT_THRESHOLD": func(config *hystrix.CommandConfig, value string) error {
+		return parseInt(&config.ErrorPercentThreshold, value)
+	},
+	"SLEEP_WINDOW": func(config *hystrix.CommandConfig, value string) error {
+		return parseMilliseconds(&config.SleepWindow, value)
+	},
+	"REQUEST_VOLUME_THRESHOLD": func(config *hystrix.CommandConfig, value string) error {
+		return parseInt(&config.RequestVolumeThreshold, value)
+	},
+}
+
+// Settings returns the hystrix config of every command
+// Unknown command names and invalid values are rejected.
+func (c Config) Settings() (map[string]hystrix.CommandConfig, error) {
+	settings := map[string]hystrix.CommandConfig{
+		InsertRecord:     {Timeout: 3000},
+		SelectRecordByID: {Timeout: 3000},
+	}
+
+	if path := os.Getenv("HYSTRIX_CONFIG"); len(path) > 0 {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var file map[string]json.RawMessage
+		if err := json.Unmarshal(content, &file); err != nil {
+			return nil, fmt.Errorf("invalid hystrix config %s: %w", path, err)
+		}
+
+		for name, raw := range file {
+			config, ok := settings[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown hystrix command %q in %s", name, path)
+			}
+
+			// the settings missing from the file keep their value
+			decoder := json.NewDecoder(strings.NewReader(string(raw)))
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(&config); err != nil {
+				return nil, fmt.Errorf("invalid hystrix config of %s: %w", name, err)
+			}
+			settings[name] = config
+		}
+	}
+
+	for _, env := range os.Environ() {
+		key, value, _ := strings.Cut(env, "=")
+		if !strings.HasPrefix(key, "HYSTRIX_") {
+			continue
+		}
+
+		for suffix, set := range envSettings {
+			command, ok := strings.CutSuffix(strings.TrimPrefix(key, "HYSTRIX_"), "_"+suffix)
+			if !ok {
+				continue
+			}
+
+			name := strings.ToLower(command)
+			config, ok := settings[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown hystrix command %q in %s", name, key)
+			}
+			if err := set(&config, value); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			settings[name] = config
+		}
+	}
+
+	for name, config := range settings {
+		if config.Timeout < 0 || config.MaxConcurrentRequests < 0 || config.RequestVolumeThreshold < 0 || config.SleepWindow < 0 || config.ErrorPercentThreshold < 0 || config.ErrorPercentThreshold > 100 {
+			return nil, fmt.Errorf("invalid hystrix config of %s: %+v", name, config)
+		}
 	}
+
+	return settings, nil
+}
+
+// Configure applies the config of every command, once at startup
+func (c Config) Configure() error {
+	settings, err := c.Settings()
+	if err != nil {
+		return err
+	}
+
+	hystrix.Configure(settings)
+
+	return nil
+}
+
+// parseMilliseconds parses a duration like 500ms, or a plain number of milliseconds
+func parseMilliseconds(target *int, value string) error {
+	if milliseconds, err := strconv.Atoi(value); err == nil {
+		*target = milliseconds
+		return nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*target = int(duration.Milliseconds())
+
+	return nil
+}
+
+func parseInt(target *int, value string) error {
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	*target = number
+
+	return nil
 }
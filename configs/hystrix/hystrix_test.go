@@ -0,0 +1,65 @@
+package hystrix
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hystrix.json")
+	err := os.WriteFile(path, []byte(`{"select_record_by_id": {"timeout": 500, "max_concurrent_requests": 100}}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HYSTRIX_CONFIG", path)
+	t.Setenv("HYSTRIX_INSERT_RECORD_SLEEP_WINDOW", "10s")
+	t.Setenv("HYSTRIX_SELECT_RECORD_BY_ID_TIMEOUT", "250ms")
+
+	settings, err := Config{}.Settings()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the env overrides the file, which overrides the defaults
+	if s := settings[SelectRecordByID]; s.Timeout != 250 || s.MaxConcurrentRequests != 100 {
+		t.Errorf("unexpected %s settings %+v", SelectRecordByID, s)
+	}
+	if s := settings[InsertRecord]; s.Timeout != 3000 || s.SleepWindow != 10000 {
+		t.Errorf("unexpected %s settings %+v", InsertRecord, s)
+	}
+}
+
+func TestSettingsErrors(t *testing.T) {
+	tests := map[string]struct {
+		file string
+		key  string
+		env  string
+	}{
+		"unknown command in file":  {file: `{"delete_record": {"timeout": 500}}`},
+		"unknown setting in file":  {file: `{"insert_record": {"timeot": 500}}`},
+		"unknown command in env":   {key: "HYSTRIX_DELETE_RECORD_TIMEOUT", env: "1s"},
+		"invalid value in env":     {key: "HYSTRIX_INSERT_RECORD_MAX_CONCURRENT_REQUESTS", env: "many"},
+		"out of range error ratio": {key: "HYSTRIX_INSERT_RECORD_ERROR_PERCENT_THRESHOLD", env: "150"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("HYSTRIX_CONFIG", "")
+			if len(test.file) > 0 {
+				path := filepath.Join(t.TempDir(), "hystrix.json")
+				if err := os.WriteFile(path, []byte(test.file), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				t.Setenv("HYSTRIX_CONFIG", path)
+			}
+			if len(test.key) > 0 {
+				t.Setenv(test.key, test.env)
+			}
+
+			if _, err := (Config{}).Settings(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
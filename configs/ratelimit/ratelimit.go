@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"gomora/infrastructures/ratelimit/types"
+)
+
+// the rate limited routes, shared by the REST and gRPC servers
+const (
+	GenerateToken = "generate_token"
+	CreateRecord  = "create_record"
+	GetRecord     = "get_record"
+	Admin         = "admin"
+	// IP limits every client ip on all the routes, before the authentication
+	IP = "ip"
+)
+
+// Config handles the rate limit configurations
+// The defaults are overridden by the json file at RATE_LIMIT_CONFIG, keyed by route name, then by the env
+// like RATE_LIMIT_GET_RECORD_RATE=50. A rate of 0 lifts the limit of the route, the rates are at most types.MaxRate.
+type Config struct{}
+
+// Limits returns the limit of every limited route
+// Unknown route names and invalid values are rejected.
+func (c Config) Limits() (map[string]types.Limit, error) {
+	limits := map[string]types.Limit{
+		GenerateToken: {Rate: 1, Burst: 10},
+		CreateRecord:  {Rate: 10, Burst: 20},
+		GetRecord:     {Rate: 100, Burst: 200},
+		Admin:         {Rate: 10, Burst: 50},
+		IP:            {Rate: 200, Burst: 400},
+	}
+
+	if path := os.Getenv("RATE_LIMIT_CONFIG"); len(path) > 0 {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var file map[string]json.RawMessage
+		if err := json.Unmarshal(content, &file); err != nil {
+			return nil, fmt.Errorf("invalid rate limit config %s: %w", path, err)
+		}
+
+		for name, raw := range file {
+			limit, ok := limits[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown rate limited route %q in %s", name, path)
+			}
+
+			// the settings missing from the file keep their value
+			decoder := json.NewDecoder(strings.NewReader(string(raw)))
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(&limit); err != nil {
+				return nil, fmt.Errorf("invalid rate limit config of %s: %w", name, err)
+			}
+			limits[name] = limit
+		}
+	}
+
+	for _, env := range os.Environ() {
+		key, value, _ := strings.Cut(env, "=")
+		route, ok := strings.CutPrefix(key, "RATE_LIMIT_")
+		if !ok {
+			continue
+		}
+
+		var err error
+		var limit types.Limit
+		var name string
+		if route, ok = strings.CutSuffix(route, "_RATE"); ok {
+			name = strings.ToLower(route)
+			limit = limits[name]
+			limit.Rate, err = strconv.ParseFloat(value, 64)
+		} else if route, ok = strings.CutSuffix(route, "_BURST"); ok {
+			name = strings.ToLower(route)
+			limit = limits[name]
+			limit.Burst, err = strconv.Atoi(value)
+		} else {
+			continue
+		}
+
+		if _, known := limits[name]; !known {
+			return nil, fmt.Errorf("unknown rate limited route %q in %s", name, key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		limits[name] = limit
+	}
+
+	for name, limit := range limits {
+		if limit.Rate < 0 || limit.Rate > types.MaxRate || limit.Burst < 0 || math.IsNaN(limit.Rate) {
+			return nil, fmt.Errorf("invalid rate limit config of %s: %+v", name, limit)
+		}
+
+		if limit.Rate == 0 {
+			delete(limits, name)
+			continue
+		}
+
+		// the bucket holds at least a second of requests
+		if limit.Burst == 0 {
+			limit.Burst = int(math.Ceil(limit.Rate))
+			limits[name] = limit
+		}
+	}
+
+	return limits, nil
+}
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratelimit.json")
+	err := os.WriteFile(path, []byte(`{"get_record": {"rate": 50, "burst": 80}, "admin": {"rate": 0}}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("RATE_LIMIT_CONFIG", path)
+	t.Setenv("RATE_LIMIT_GET_RECORD_BURST", "100")
+	t.Setenv("RATE_LIMIT_CREATE_RECORD_RATE", "2.5")
+	t.Setenv("RATE_LIMIT_IP_RATE", "1e6")
+
+	limits, err := Config{}.Limits()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the env overrides the file, which overrides the defaults
+	if l := limits[GetRecord]; l.Rate != 50 || l.Burst != 100 {
+		t.Errorf("unexpected %s limit %+v", GetRecord, l)
+	}
+	if l := limits[CreateRecord]; l.Rate != 2.5 || l.Burst != 20 {
+		t.Errorf("unexpected %s limit %+v", CreateRecord, l)
+	}
+
+	// up to a token per microsecond
+	if l := limits[IP]; l.Rate != 1e6 || l.Burst != 400 {
+		t.Errorf("unexpected %s limit %+v", IP, l)
+	}
+
+	// a rate of 0 lifts the limit
+	if _, ok := limits[Admin]; ok {
+		t.Errorf("expected %s to be unlimited", Admin)
+	}
+}
+
+func TestLimitsErrors(t *testing.T) {
+	tests := map[string]struct {
+		file string
+		key  string
+		env  string
+	}{
+		"unknown route in file":   {file: `{"delete_record": {"rate": 1}}`},
+		"unknown setting in file": {file: `{"get_record": {"rat": 1}}`},
+		"unknown route in env":    {key: "RATE_LIMIT_DELETE_RECORD_RATE", env: "1"},
+		"invalid rate":            {key: "RATE_LIMIT_GET_RECORD_RATE", env: "fast"},
+		"negative burst":          {key: "RATE_LIMIT_GET_RECORD_BURST", env: "-1"},
+		"negative rate":           {key: "RATE_LIMIT_GET_RECORD_RATE", env: "-1"},
+		"rate over the max":       {key: "RATE_LIMIT_GET_RECORD_RATE", env: "1000001"},
+		"infinite rate":           {file: `{"ip": {"rate": 1e400}}`},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if len(test.file) > 0 {
+				path := filepath.Join(t.TempDir(), "ratelimit.json")
+				if err := os.WriteFile(path, []byte(test.file), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				t.Setenv("RATE_LIMIT_CONFIG", path)
+			}
+			if len(test.key) > 0 {
+				t.Setenv(test.key, test.env)
+			}
+
+			if _, err := (Config{}).Limits(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
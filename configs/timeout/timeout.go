@@ -0,0 +1,97 @@
+package timeout
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// the routes with a request timeout, shared by the REST and gRPC servers
+const (
+	GenerateToken = "generate_token"
+	CreateRecord  = "create_record"
+	GetRecord     = "get_record"
+	Admin         = "admin"
+)
+
+// Config handles the request timeout configurations
+// The defaults are overridden by the json file at REQUEST_TIMEOUT_CONFIG, keyed by route name with durations
+// like "500ms" or milliseconds, then by the env like REQUEST_TIMEOUT_GET_RECORD=500ms. A timeout of 0 lifts
+// the timeout of the route.
+type Config struct{}
+
+// Timeouts returns the timeout of every route with one, the maximum a client can ask for
+// Unknown route names and invalid values are rejected.
+func (c Config) Timeouts() (map[string]time.Duration, error) {
+	timeouts := map[string]time.Duration{
+		GenerateToken: time.Second * 5,
+		CreateRecord:  time.Second * 5,
+		GetRecord:     time.Second * 3,
+		Admin:         time.Second * 60,
+	}
+
+	if path := os.Getenv("REQUEST_TIMEOUT_CONFIG"); len(path) > 0 {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var file map[string]interface{}
+		if err := json.Unmarshal(content, &file); err != nil {
+			return nil, fmt.Errorf("invalid request timeout config %s: %w", path, err)
+		}
+
+		for name, value := range file {
+			if _, ok := timeouts[name]; !ok {
+				return nil, fmt.Errorf("unknown route %q in %s", name, path)
+			}
+
+			var timeout time.Duration
+			switch value := value.(type) {
+			case float64:
+				timeout = time.Duration(value * float64(time.Millisecond))
+			case string:
+				timeout, err = time.ParseDuration(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid request timeout of %s: %w", name, err)
+				}
+			default:
+				return nil, fmt.Errorf("invalid request timeout of %s: %v", name, value)
+			}
+			timeouts[name] = timeout
+		}
+	}
+
+	for _, env := range os.Environ() {
+		key, value, _ := strings.Cut(env, "=")
+		route, ok := strings.CutPrefix(key, "REQUEST_TIMEOUT_")
+		if !ok || route == "CONFIG" {
+			continue
+		}
+
+		name := strings.ToLower(route)
+		if _, known := timeouts[name]; !known {
+			return nil, fmt.Errorf("unknown route %q in %s", name, key)
+		}
+
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		timeouts[name] = timeout
+	}
+
+	for name, timeout := range timeouts {
+		if timeout < 0 {
+			return nil, fmt.Errorf("invalid request timeout of %s: %s", name, timeout)
+		}
+
+		if timeout == 0 {
+			delete(timeouts, name)
+		}
+	}
+
+	return timeouts, nil
+}
@@ -0,0 +1,72 @@
+package timeout
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTimeouts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timeout.json")
+	err := os.WriteFile(path, []byte(`{"get_record": "1s", "create_record": 2500, "admin": 0}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("REQUEST_TIMEOUT_CONFIG", path)
+	t.Setenv("REQUEST_TIMEOUT_GET_RECORD", "500ms")
+
+	timeouts, err := Config{}.Timeouts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the env overrides the file, which overrides the defaults
+	if timeout := timeouts[GetRecord]; timeout != time.Millisecond*500 {
+		t.Errorf("unexpected %s timeout %s", GetRecord, timeout)
+	}
+	if timeout := timeouts[CreateRecord]; timeout != time.Millisecond*2500 {
+		t.Errorf("unexpected %s timeout %s", CreateRecord, timeout)
+	}
+	if timeout := timeouts[GenerateToken]; timeout != time.Second*5 {
+		t.Errorf("unexpected %s timeout %s", GenerateToken, timeout)
+	}
+
+	// a timeout of 0 lifts the timeout
+	if _, ok := timeouts[Admin]; ok {
+		t.Errorf("expected %s to have no timeout", Admin)
+	}
+}
+
+func TestTimeoutsErrors(t *testing.T) {
+	tests := map[string]struct {
+		file string
+		key  string
+		env  string
+	}{
+		"unknown route in file": {file: `{"delete_record": "1s"}`},
+		"invalid value in file": {file: `{"get_record": true}`},
+		"unknown route in env":  {key: "REQUEST_TIMEOUT_DELETE_RECORD", env: "1s"},
+		"invalid timeout":       {key: "REQUEST_TIMEOUT_GET_RECORD", env: "500"},
+		"negative timeout":      {key: "REQUEST_TIMEOUT_GET_RECORD", env: "-1s"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if len(test.file) > 0 {
+				path := filepath.Join(t.TempDir(), "timeout.json")
+				if err := os.WriteFile(path, []byte(test.file), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				t.Setenv("REQUEST_TIMEOUT_CONFIG", path)
+			}
+			if len(test.key) > 0 {
+				t.Setenv(test.key, test.env)
+			}
+
+			if _, err := (Config{}).Timeouts(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+)
+
+// Group coalesces the concurrent calls of the same key into a single one, like singleflight
+type Group[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*call[T]
+}
+
+type call[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// Do runs the function once for the callers of the key waiting on it, and returns its result to all of them
+// The call is not tied to any caller: a caller whose context is done stops waiting and gets its error,
+// the call goes on for the others. A panic of the function is returned to all of them as an error.
+func (g *Group[T]) Do(ctx context.Context, key string, fn func() (T, error)) (T, error) {
+	g.mu.Lock()
+	c, ok := g.calls[key]
+	if !ok {
+		c = &call[T]{done: make(chan struct{})}
+		if g.calls == nil {
+			g.calls = map[string]*call[T]{}
+		}
+		g.calls[key] = c
+
+		go func() {
+			defer func() {
+				// the call runs on its own goroutine, a panic would crash the server
+				if p := recover(); p != nil {
+					log.Printf("[CACHE] call of %q panicked: %v\n%s", key, p, debug.Stack())
+					c.err = fmt.Errorf("cache: call of %q panicked: %v", key, p)
+				}
+
+				g.mu.Lock()
+				delete(g.calls, key)
+				g.mu.Unlock()
+				close(c.done)
+			}()
+
+			c.value, c.err = fn()
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.value, c.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGroup(t *testing.T) {
+	var group Group[int]
+	var calls int
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	results := make([]int, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = group.Do(context.Background(), "key", func() (int, error) {
+				calls++
+				<-release
+				return 42, nil
+			})
+		}(i)
+	}
+
+	// let the callers join the first call before releasing it
+	time.Sleep(time.Millisecond * 50)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("expected a single call, got %d", calls)
+	}
+	for _, result := range results {
+		if result != 42 {
+			t.Errorf("expected 42, got %d", result)
+		}
+	}
+}
+
+func TestGroupContext(t *testing.T) {
+	var group Group[int]
+	release := make(chan struct{})
+	defer close(release)
+
+	// the caller giving up doesn't wait for the call
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	_, err := group.Do(ctx, "key", func() (int, error) {
+		<-release
+		return 42, nil
+	})
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestGroupPanic(t *testing.T) {
+	var group Group[int]
+
+	// the panic is returned to the callers instead of crashing the server
+	_, err := group.Do(context.Background(), "key", func() (int, error) {
+		panic("boom")
+	})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected the panic as an error, got %v", err)
+	}
+
+	// and the key is free for the next call
+	value, err := group.Do(context.Background(), "key", func() (int, error) {
+		return 42, nil
+	})
+	if err != nil || value != 42 {
+		t.Errorf("expected 42, got %d, %v", value, err)
+	}
+}
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	c := NewLRU[string, int](2, 0)
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	// reading a keeps it, b is the least recently used
+	if value, ok := c.Get("a"); !ok || value != 1 {
+		t.Errorf("expected 1, got %d (%t)", value, ok)
+	}
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	if value, ok := c.Get("c"); !ok || value != 3 || c.Len() != 2 {
+		t.Errorf("expected 3, got %d (%t) with %d entries", value, ok, c.Len())
+	}
+
+	c.Delete("a")
+	if _, ok := c.Get("a"); ok {
+		t.Error("expected a to be deleted")
+	}
+}
+
+func TestLRUExpiry(t *testing.T) {
+	c := NewLRU[string, int](2, time.Millisecond*10)
+	c.Set("a", 1)
+
+	time.Sleep(time.Millisecond * 20)
+	if _, ok := c.Get("a"); ok || c.Len() != 0 {
+		t.Error("expected a to expire")
+	}
+}
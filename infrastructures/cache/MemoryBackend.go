@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// MemoryBackend stores the entries in an in-process LRU
+type MemoryBackend struct {
+	lru *LRU[string, []byte]
+}
+
+// NewMemoryBackend returns a backend holding up to size entries for the ttl
+func NewMemoryBackend(size int, ttl time.Duration) *MemoryBackend {
+	return &MemoryBackend{lru: NewLRU[string, []byte](size, ttl)}
+}
+
+// Get returns the value of the key, false when missing or expired
+func (b *MemoryBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, ok := b.lru.Get(key)
+
+	return value, ok, nil
+}
+
+// Set stores the value of the key
+func (b *MemoryBackend) Set(ctx context.Context, key string, value []byte) error {
+	b.lru.Set(key, value)
+
+	return nil
+}
+
+// Delete removes the keys
+func (b *MemoryBackend) Delete(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		b.lru.Delete(key)
+	}
+
+	return nil
+}
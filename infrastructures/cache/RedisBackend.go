@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"gomora/infrastructures/cache/types"
+	"gomora/infrastructures/redis"
+	redisTypes "gomora/infrastructures/redis/types"
+)
+
+// RedisBackend stores the entries in redis, shared by every instance
+type RedisBackend struct {
+	client *redis.Client
+	params types.RedisParams
+}
+
+// NewRedisBackend returns a backend storing the entries for the ttl of the params
+func NewRedisBackend(params types.RedisParams) *RedisBackend {
+	return &RedisBackend{
+		client: redis.NewClient(redisTypes.ClientParams{
+			Addr:     params.Addr,
+			Password: params.Password,
+			DB:       params.DB,
+			PoolSize: params.PoolSize,
+			Timeout:  params.Timeout,
+		}),
+		params: params,
+	}
+}
+
+// Get returns the value of the key, false when missing or expired
+func (b *RedisBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := b.client.Do(ctx, "GET", b.params.Prefix+key)
+	if err != nil || reply == nil {
+		return nil, false, err
+	}
+
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("redis: unexpected reply %v", reply)
+	}
+
+	return value, true, nil
+}
+
+// Set stores the value of the key
+func (b *RedisBackend) Set(ctx context.Context, key string, value []byte) error {
+	args := []string{"SET", b.params.Prefix + key, string(value)}
+	if b.params.TTL > 0 {
+		args = append(args, "PX", strconv.FormatInt(b.params.TTL.Milliseconds(), 10))
+	}
+
+	_, err := b.client.Do(ctx, args...)
+
+	return err
+}
+
+// Delete removes the keys
+func (b *RedisBackend) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	args := []string{"DEL"}
+	for _, key := range keys {
+		args = append(args, b.params.Prefix+key)
+	}
+
+	_, err := b.client.Do(ctx, args...)
+
+	return err
+}
+
+// Close closes the idle connections
+func (b *RedisBackend) Close() error {
+	return b.client.Close()
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gomora/infrastructures/cache/types"
+	"gomora/infrastructures/redis/redistest"
+)
+
+func TestRedisBackend(t *testing.T) {
+	server := redistest.NewServer(t, "secret")
+	backend := NewRedisBackend(types.RedisParams{
+		Addr:     server.Addr(),
+		Password: "secret",
+		DB:       2,
+		TTL:      time.Millisecond * 50,
+		Prefix:   "test:",
+	})
+	defer backend.Close()
+	ctx := context.Background()
+
+	if _, ok, err := backend.Get(ctx, "a"); err != nil || ok {
+		t.Fatalf("expected a miss, got %v %v", ok, err)
+	}
+
+	// the values are binary safe
+	value := []byte("line\r\nbreak")
+	if err := backend.Set(ctx, "a", value); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok, err := backend.Get(ctx, "a"); err != nil || !ok || string(got) != string(value) {
+		t.Fatalf("expected %q, got %q %v %v", value, got, ok, err)
+	}
+
+	if _, prefixed := server.Value("test:a"); !prefixed || !server.Selected(2) {
+		t.Errorf("expected the prefixed key in db 2")
+	}
+
+	if err := backend.Delete(ctx, "a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := backend.Get(ctx, "a"); ok {
+		t.Error("expected the deleted key to miss")
+	}
+
+	// the entries expire after the ttl
+	if err := backend.Set(ctx, "b", []byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond * 100)
+	if _, ok, _ := backend.Get(ctx, "b"); ok {
+		t.Error("expected the expired key to miss")
+	}
+}
+
+func TestRedisBackendErrors(t *testing.T) {
+	server := redistest.NewServer(t, "secret")
+	ctx := context.Background()
+
+	backend := NewRedisBackend(types.RedisParams{Addr: server.Addr(), Password: "wrong"})
+	if _, _, err := backend.Get(ctx, "a"); err == nil || !strings.Contains(err.Error(), "WRONGPASS") {
+		t.Errorf("expected an auth error, got %v", err)
+	}
+
+	// the unreachable servers fail within the timeout
+	server.Close()
+	backend = NewRedisBackend(types.RedisParams{Addr: server.Addr(), Timeout: time.Millisecond * 100})
+	if err := backend.Set(ctx, "a", []byte("a")); err == nil {
+		t.Error("expected a dial error")
+	}
+}
@@ -0,0 +1,16 @@
+package types
+
+import (
+	"context"
+)
+
+// CacheBackendInterface contains the methods of the cache stores
+// The entries expire after the ttl the backend was created with.
+type CacheBackendInterface interface {
+	// Get returns the value of the key, false when missing or expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores the value of the key
+	Set(ctx context.Context, key string, value []byte) error
+	// Delete removes the keys
+	Delete(ctx context.Context, keys ...string) error
+}
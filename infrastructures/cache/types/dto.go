@@ -0,0 +1,16 @@
+package types
+
+import (
+	"time"
+)
+
+// RedisParams holds the settings of the redis backend
+type RedisParams struct {
+	Addr     string // host:port
+	Password string
+	DB       int
+	TTL      time.Duration
+	Prefix   string        // prepended to every key, to share the database with other services
+	PoolSize int           // idle connections kept open, defaults to 16
+	Timeout  time.Duration // dial, read and write timeout, defaults to 1s
+}
@@ -0,0 +1,380 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NilVersion is the version of a database without any migration applied
+const NilVersion = -1
+
+var (
+	// ErrNoChange is returned when the database is already at the requested version
+	ErrNoChange = errors.New("no change")
+	// ErrMigrationLocked is returned when another instance holds the migration lock
+	ErrMigrationLocked = errors.New("migration lock is held by another instance")
+	// ErrUnknownVersion is returned when the requested version has no migration
+	ErrUnknownVersion = errors.New("unknown migration version")
+)
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// MigrationDialect holds the driver specific parts of the migrations
+type MigrationDialect interface {
+	// Lock takes the advisory lock of the migrations on the connection, so that concurrent instances don't race
+	Lock(ctx context.Context, conn *sql.Conn) error
+	// Unlock releases the advisory lock
+	Unlock(ctx context.Context, conn *sql.Conn) error
+	// Statements splits the migration into the statements to execute
+	Statements(body string) []string
+}
+
+// Migrator applies the schema migrations
+// It keeps the schema_migrations table of golang-migrate, so databases migrated with the cli keep their version.
+type Migrator struct {
+	DB         *sqlx.DB
+	Dialect    MigrationDialect
+	migrations []migration
+}
+
+type migration struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
+
+// execer is satisfied by both the connection pool and a single connection
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// NewMigrator reads the <version>_<name>.(up|down).sql files of fsys
+func NewMigrator(db *sqlx.DB, dialect MigrationDialect, fsys fs.FS) (*Migrator, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*migration{}
+	for _, entry := range entries {
+		matches := migrationFileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+
+		version, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration %s: %w", entry.Name(), err)
+		}
+
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{version: version, name: matches[2]}
+			byVersion[version] = m
+		}
+
+		if matches[3] == "up" {
+			m.up = string(body)
+		} else {
+			m.down = string(body)
+		}
+	}
+
+	migrator := &Migrator{DB: db, Dialect: dialect}
+	for _, m := range byVersion {
+		migrator.migrations = append(migrator.migrations, *m)
+	}
+	sort.Slice(migrator.migrations, func(i, j int) bool {
+		return migrator.migrations[i].version < migrator.migrations[j].version
+	})
+
+	return migrator, nil
+}
+
+// Up applies the next steps migrations, or all of them when steps is 0
+func (m *Migrator) Up(ctx context.Context, steps int) error {
+	return m.withLock(ctx, func(conn *sql.Conn, current int) error {
+		target := current
+		for _, mg := range m.migrations {
+			if mg.version <= current {
+				continue
+			}
+
+			target = mg.version
+			if steps--; steps == 0 {
+				break
+			}
+		}
+
+		return m.migrate(ctx, conn, current, target)
+	})
+}
+
+// Down reverts the last steps migrations, or all of them when steps is 0
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.withLock(ctx, func(conn *sql.Conn, current int) error {
+		target := current
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if m.migrations[i].version > current {
+				continue
+			}
+
+			target = m.previousVersion(i)
+			if steps--; steps == 0 {
+				break
+			}
+		}
+
+		return m.migrate(ctx, conn, current, target)
+	})
+}
+
+// Goto migrates up or down to the given version
+func (m *Migrator) Goto(ctx context.Context, version int) error {
+	if version != NilVersion && m.indexOf(version) < 0 {
+		return ErrUnknownVersion
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn, current int) error {
+		return m.migrate(ctx, conn, current, version)
+	})
+}
+
+// Force sets the version without running any migration, clearing the dirty flag
+func (m *Migrator) Force(ctx context.Context, version int) error {
+	if version < NilVersion {
+		return ErrUnknownVersion
+	}
+
+	conn, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer m.unlock(conn)
+
+	return m.setVersion(ctx, conn, version, false)
+}
+
+// Version returns the current version and whether the last migration failed halfway
+func (m *Migrator) Version(ctx context.Context) (int, bool, error) {
+	if err := ensureVersionTable(ctx, m.DB); err != nil {
+		return NilVersion, false, err
+	}
+
+	return readVersion(ctx, m.DB)
+}
+
+// withLock runs fn on a single connection holding the advisory lock, so that concurrent instances don't race
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn, current int) error) error {
+	conn, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer m.unlock(conn)
+
+	current, dirty, err := readVersion(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("database is dirty at version %d, fix it and force the version", current)
+	}
+
+	return fn(conn, current)
+}
+
+func (m *Migrator) lock(ctx context.Context) (*sql.Conn, error) {
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.Dialect.Lock(ctx, conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if err := ensureVersionTable(ctx, conn); err != nil {
+		m.unlock(conn)
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (m *Migrator) unlock(conn *sql.Conn) {
+	_ = m.Dialect.Unlock(context.Background(), conn)
+	conn.Close()
+}
+
+// migrate runs the migrations between the current and the target version, one at a time
+func (m *Migrator) migrate(ctx context.Context, conn *sql.Conn, current, target int) error {
+	if current == target {
+		return ErrNoChange
+	}
+
+	if target > current {
+		for _, mg := range m.migrations {
+			if mg.version > current && mg.version <= target {
+				if err := m.apply(ctx, conn, mg.version, mg.up); err != nil {
+					return fmt.Errorf("migration %d_%s up: %w", mg.version, mg.name, err)
+				}
+			}
+		}
+
+		return nil
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mg := m.migrations[i]
+		if mg.version <= current && mg.version > target {
+			if err := m.apply(ctx, conn, m.previousVersion(i), mg.down); err != nil {
+				return fmt.Errorf("migration %d_%s down: %w", mg.version, mg.name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *Migrator) indexOf(version int) int {
+	for i, mg := range m.migrations {
+		if mg.version == version {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (m *Migrator) previousVersion(i int) int {
+	if i == 0 {
+		return NilVersion
+	}
+
+	return m.migrations[i-1].version
+}
+
+// apply runs the statements of a migration, leaving the version dirty if one of them fails
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, version int, body string) error {
+	if err := m.setVersion(ctx, conn, version, true); err != nil {
+		return err
+	}
+
+	for _, stmt := range m.Dialect.Statements(body) {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+
+	return m.setVersion(ctx, conn, version, false)
+}
+
+func ensureVersionTable(ctx context.Context, db execer) error {
+	_, err := db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)")
+
+	return err
+}
+
+func readVersion(ctx context.Context, db execer) (int, bool, error) {
+	var version int
+	var dirty bool
+
+	err := db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return NilVersion, false, nil
+	}
+	if err != nil {
+		return NilVersion, false, err
+	}
+
+	return version, dirty, nil
+}
+
+func (m *Migrator) setVersion(ctx context.Context, conn *sql.Conn, version int, dirty bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations"); err != nil {
+		return err
+	}
+	if version != NilVersion {
+		if _, err := tx.ExecContext(ctx, m.DB.Rebind("INSERT INTO schema_migrations (version, dirty) VALUES (?, ?)"), version, dirty); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SplitStatements splits a migration into its statements, skipping comments and the semicolons of quoted strings
+// Compound statements that need a custom DELIMITER are not supported.
+func SplitStatements(body string) []string {
+	var stmts []string
+	var current strings.Builder
+
+	flush := func() {
+		if stmt := strings.TrimSpace(current.String()); len(stmt) > 0 {
+			stmts = append(stmts, stmt)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := i + 1
+			for ; end < len(body) && body[end] != c; end++ {
+				if body[end] == '\\' && c != '`' {
+					end++
+				}
+			}
+			if end >= len(body) {
+				end = len(body) - 1
+			}
+			current.WriteString(body[i : end+1])
+			i = end
+		case c == '#' || (c == '-' && strings.HasPrefix(body[i:], "-- ")):
+			for i < len(body) && body[i] != '\n' {
+				i++
+			}
+			current.WriteByte('\n')
+		case c == '/' && strings.HasPrefix(body[i:], "/*"):
+			end := strings.Index(body[i+2:], "*/")
+			if end < 0 {
+				i = len(body)
+			} else {
+				i += end + 3
+			}
+			current.WriteByte(' ')
+		case c == ';':
+			flush()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+
+	return stmts
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSplitStatements(t *testing.T) {
+	body := "-- create the table\nCREATE TABLE `a;b` (`c` varchar(8) DEFAULT ';');\n/* comment; */\n# note\nINSERT INTO `a;b` VALUES ('it\\'s;');\n"
+
+	expected := []string{
+		"CREATE TABLE `a;b` (`c` varchar(8) DEFAULT ';')",
+		"INSERT INTO `a;b` VALUES ('it\\'s;')",
+	}
+	if stmts := SplitStatements(body); !reflect.DeepEqual(stmts, expected) {
+		t.Errorf("unexpected statements %q", stmts)
+	}
+}
+
+func TestNewMigrator(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000010_b.up.sql":   {Data: []byte("b up")},
+		"000010_b.down.sql": {Data: []byte("b down")},
+		"000002_a.up.sql":   {Data: []byte("a up")},
+		"000002_a.down.sql": {Data: []byte("a down")},
+		"migrations.go":     {Data: []byte("package migrations")},
+	}
+
+	migrator, err := NewMigrator(nil, nil, fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []migration{
+		{version: 2, name: "a", up: "a up", down: "a down"},
+		{version: 10, name: "b", up: "b up", down: "b down"},
+	}
+	if !reflect.DeepEqual(migrator.migrations, expected) {
+		t.Errorf("unexpected migrations %+v", migrator.migrations)
+	}
+	if previous := migrator.previousVersion(0); previous != NilVersion {
+		t.Errorf("expected the nil version before the first migration, got %d", previous)
+	}
+}
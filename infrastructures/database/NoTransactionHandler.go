@@ -0,0 +1,14 @@
+package database
+
+import (
+	"context"
+)
+
+// NoTransactionHandler runs the units of work without a transaction, for the repositories kept in memory
+// A failing unit of work is not rolled back, the statements it already ran stay applied.
+type NoTransactionHandler struct{}
+
+// WithTransaction runs the unit of work with the context as is
+func (NoTransactionHandler) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// replicaEjectDuration is how long a replica stays out of the rotation after a connection failure
+const replicaEjectDuration = time.Second * 30
+
+// namedPreparer is satisfied by the connections and the transactions
+type namedPreparer interface {
+	PrepareNamedContext(ctx context.Context, query string) (*sqlx.NamedStmt, error)
+}
+
+type replica struct {
+	db           *sqlx.DB
+	ejectedUntil atomic.Int64 // unix nano
+}
+
+func (r *replica) eject() {
+	r.ejectedUntil.Store(time.Now().Add(replicaEjectDuration).UnixNano())
+}
+
+func (r *replica) healthy(now time.Time) bool {
+	return r.ejectedUntil.Load() <= now.UnixNano()
+}
+
+// AddReplica adds a read replica to the rotation
+// A replica that is down is ejected instead of failing the connection.
+func (h *SQLHandler) AddReplica(db *sqlx.DB) {
+	r := &replica{db: db}
+	if err := db.Ping(); err != nil {
+		fmt.Printf("[SERVER] Replica is not responding: %s\n", err.Error())
+		r.eject()
+	}
+
+	h.replicas = append(h.replicas, r)
+}
+
+// reader returns where the read goes: the transaction of the context, the next healthy replica or the primary
+func (h *SQLHandler) reader(ctx context.Context) (namedPreparer, *replica) {
+	if t, ok := h.transactionFromContext(ctx); ok {
+		return t.tx, nil
+	}
+
+	if primaryRequested(ctx) || len(h.replicas) == 0 {
+		return h.Conn, nil
+	}
+
+	// round-robin over the healthy replicas, falling back to the primary
+	now := time.Now()
+	for range h.replicas {
+		r := h.replicas[(h.next.Add(1)-1)%uint64(len(h.replicas))]
+		if r.healthy(now) {
+			return r.db, r
+		}
+	}
+
+	return h.Conn, nil
+}
+
+// observe ejects the replica from the rotation when the read failed to reach it
+func (h *SQLHandler) observe(r *replica, err error) {
+	if r != nil && h.Dialect.IsConnectionError(err) {
+		r.eject()
+	}
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func TestReaderRouting(t *testing.T) {
+	open := func() *sqlx.DB {
+		// the connections are never used
+		return sqlx.NewDb(nil, "mysql")
+	}
+
+	h := &SQLHandler{Conn: open(), Dialect: testDialect{}}
+	first, second := &replica{db: open()}, &replica{db: open()}
+	h.replicas = []*replica{first, second}
+
+	// round-robin over the replicas
+	if _, r := h.reader(context.Background()); r != first {
+		t.Error("expected the first replica")
+	}
+	if _, r := h.reader(context.Background()); r != second {
+		t.Error("expected the second replica")
+	}
+
+	// a connection failure ejects the replica
+	h.observe(first, driver.ErrBadConn)
+	for i := 0; i < 3; i++ {
+		if _, r := h.reader(context.Background()); r != second {
+			t.Error("expected the ejected replica to be skipped")
+		}
+	}
+
+	// the primary is used when asked or when no replica is healthy
+	if db, r := h.reader(WithPrimary(context.Background())); db != h.Conn || r != nil {
+		t.Error("expected the primary")
+	}
+	h.observe(second, driver.ErrBadConn)
+	if db, r := h.reader(context.Background()); db != h.Conn || r != nil {
+		t.Error("expected the primary when all the replicas are ejected")
+	}
+}
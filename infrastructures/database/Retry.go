@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/rand"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultMaxAttempts = 3
+	defaultBaseDelay   = time.Millisecond * 50
+	defaultMaxDelay    = time.Second
+)
+
+// IsConnectionError returns true for the driver-neutral errors of a statement that failed to reach the server
+func IsConnectionError(err error) bool {
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// IsUnsentError returns true for the connection errors raised before the statement was sent
+// The server never saw the statement, unlike the resets and broken pipes that may follow its execution.
+func IsUnsentError(err error) bool {
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// retry runs the statement until it succeeds, fails with a non retriable error or runs out of attempts
+// The writes may have been applied when the connection was lost, they are only retried on the deadlocks,
+// lock wait timeouts and the connection errors raised before they were sent. The reads are retried on any.
+// Statements of a transaction are not retried one by one: a deadlock rolls back the whole transaction,
+// so the failure is flagged for WithTransaction to replay the unit of work instead.
+func (h *SQLHandler) retry(ctx context.Context, idempotent bool, fn func() error) error {
+	if t, ok := h.transactionFromContext(ctx); ok {
+		err := fn()
+		if err != nil && h.Dialect.IsRetriable(err) {
+			t.retriable = true
+		}
+
+		return err
+	}
+
+	return h.retryAttempts(ctx, func() (bool, error) {
+		err := fn()
+		if err == nil || !h.Dialect.IsRetriable(err) {
+			return false, err
+		}
+
+		return idempotent || !h.Dialect.IsConnectionError(err) || IsUnsentError(err), err
+	})
+}
+
+// retryAttempts runs fn with exponential backoff and full jitter between the attempts
+// fn reports whether its failure is worth another attempt.
+func (h *SQLHandler) retryAttempts(ctx context.Context, fn func() (bool, error)) error {
+	maxAttempts := h.RetryPolicy.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	if retryDisabled(ctx) {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		retriable, err := fn()
+		if err == nil || !retriable || attempt >= maxAttempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(h.backoff(attempt)):
+		}
+	}
+}
+
+// backoff returns a random delay up to the exponential delay of the attempt
+func (h *SQLHandler) backoff(attempt int) time.Duration {
+	baseDelay := h.RetryPolicy.BaseDelay
+	if baseDelay <= 0 {
+		baseDelay = defaultBaseDelay
+	}
+	maxDelay := h.RetryPolicy.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxDelay
+	}
+
+	delay := maxDelay
+	if shift := attempt - 1; shift < 20 && baseDelay<<shift < maxDelay {
+		delay = baseDelay << shift
+	}
+
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"gomora/infrastructures/database/types"
+)
+
+var errDeadlock = errors.New("deadlock")
+
+// testDialect retries errDeadlock and the driver-neutral connection errors
+type testDialect struct{}
+
+func (testDialect) IsConnectionError(err error) bool { return IsConnectionError(err) }
+
+func (testDialect) IsRetriable(err error) bool {
+	return errors.Is(err, errDeadlock) || IsConnectionError(err)
+}
+
+func (testDialect) TranslateError(err error) error { return err }
+
+func TestRetryAttempts(t *testing.T) {
+	h := &SQLHandler{
+		RetryPolicy: types.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Microsecond},
+		Dialect:     testDialect{},
+	}
+
+	attempts := 0
+	err := h.retry(context.Background(), false, func() error {
+		attempts++
+		if attempts < 3 {
+			return errDeadlock
+		}
+
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("expected success after 3 attempts, got %v after %d", err, attempts)
+	}
+
+	attempts = 0
+	err = h.retry(context.Background(), false, func() error {
+		attempts++
+
+		return driver.ErrBadConn
+	})
+	if !errors.Is(err, driver.ErrBadConn) || attempts != 3 {
+		t.Errorf("expected the connection error after 3 attempts, got %v after %d", err, attempts)
+	}
+
+	attempts = 0
+	_ = h.retry(context.Background(), false, func() error {
+		attempts++
+
+		return errors.New("syntax error")
+	})
+	if attempts != 1 {
+		t.Errorf("expected a single attempt for a non retriable error, got %d", attempts)
+	}
+
+	attempts = 0
+	_ = h.retry(WithoutRetry(context.Background()), false, func() error {
+		attempts++
+
+		return errDeadlock
+	})
+	if attempts != 1 {
+		t.Errorf("expected a single attempt without retry, got %d", attempts)
+	}
+
+	// a write that lost its connection may have been applied, a read can run again
+	for _, idempotent := range []bool{false, true} {
+		attempts = 0
+		_ = h.retry(context.Background(), idempotent, func() error {
+			attempts++
+
+			return io.ErrUnexpectedEOF
+		})
+		if expected := map[bool]int{false: 1, true: 3}[idempotent]; attempts != expected {
+			t.Errorf("expected %d attempts of the idempotent %t statement, got %d", expected, idempotent, attempts)
+		}
+	}
+}
+
+func TestReplayable(t *testing.T) {
+	h := &SQLHandler{Dialect: testDialect{}}
+
+	tests := []struct {
+		name     string
+		t        *transaction
+		err      error
+		expected bool
+	}{
+		{name: "success", t: &transaction{}, expected: false},
+		{name: "begin lost its connection", err: io.ErrUnexpectedEOF, expected: true},
+		{name: "statement deadlocked", t: &transaction{}, err: errDeadlock, expected: true},
+		{name: "statement error flagged retriable", t: &transaction{retriable: true}, err: errors.New("translated"), expected: true},
+		{name: "statement lost its connection", t: &transaction{}, err: io.ErrUnexpectedEOF, expected: true},
+		{name: "unit of work failed", t: &transaction{}, err: errors.New("failed"), expected: false},
+		{name: "commit deadlocked", t: &transaction{committing: true}, err: errDeadlock, expected: true},
+		{name: "commit lost its connection", t: &transaction{committing: true}, err: io.ErrUnexpectedEOF, expected: false},
+		{name: "commit failed before it was sent", t: &transaction{committing: true}, err: driver.ErrBadConn, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if replayable := h.replayable(test.t, test.err); replayable != test.expected {
+				t.Errorf("expected %t, got %t", test.expected, replayable)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"gomora/infrastructures/database/types"
+)
+
+// defaultConnMaxLifetime recycles the connections before the server closes them
+const defaultConnMaxLifetime = time.Minute * 4
+
+// Dialect holds the driver specific behaviour of the handler
+type Dialect interface {
+	// IsConnectionError returns true when the statement failed to reach the server
+	IsConnectionError(err error) bool
+	// IsRetriable returns true for the deadlocks, serialization failures and connection errors
+	IsRetriable(err error) bool
+	// TranslateError wraps the driver errors with the neutral errors of this package
+	TranslateError(err error) error
+}
+
+// SQLHandler implements the driver-neutral operations of the database handlers
+// The named statements are bound to the placeholders of the driver the connection was opened with.
+type SQLHandler struct {
+	Conn               *sqlx.DB
+	RetryPolicy        types.RetryPolicy
+	Dialect            Dialect
+	SlowQueryThreshold time.Duration // statements taking longer are logged, 0 disables the log
+
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+// Begin starts a new transaction
+func (h *SQLHandler) Begin() (*sqlx.Tx, error) {
+	// begin transaction
+	tx, err := h.Conn.Beginx()
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// ConfigurePool applies the pool settings to the connection
+func ConfigurePool(db *sqlx.DB, params types.PoolParams) {
+	if params.ConnMaxLifetime <= 0 {
+		params.ConnMaxLifetime = defaultConnMaxLifetime
+	}
+
+	db.SetConnMaxLifetime(params.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(params.ConnMaxIdleTime)
+	if params.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(params.MaxOpenConns)
+	}
+	if params.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(params.MaxIdleConns)
+	}
+}
+
+// Stats returns the connection pool statistics of the primary and the replicas
+func (h *SQLHandler) Stats() types.DBStats {
+	stats := types.DBStats{
+		Primary:  h.Conn.Stats(),
+		Replicas: []sql.DBStats{},
+	}
+	for _, r := range h.replicas {
+		stats.Replicas = append(stats.Replicas, r.db.Stats())
+	}
+
+	return stats
+}
+
+// Close closes the primary and the replica connections
+func (h *SQLHandler) Close() error {
+	for _, r := range h.replicas {
+		_ = r.db.Close()
+	}
+
+	return h.Conn.Close()
+}
+
+// Execute executes the statement following NamedExec
+// It requires a valid sql statement and its struct, the statement joins the transaction of the context if any
+func (h *SQLHandler) Execute(ctx context.Context, stmt string, model interface{}) (sql.Result, error) {
+	defer h.logSlowQuery(stmt, time.Now())
+
+	var res sql.Result
+
+	err := h.retry(ctx, false, func() error {
+		var err error
+
+		if t, ok := h.transactionFromContext(ctx); ok {
+			res, err = t.tx.NamedExecContext(ctx, stmt, model)
+		} else {
+			res, err = h.Conn.NamedExecContext(ctx, stmt, model)
+		}
+
+		return err
+	})
+	if err != nil {
+		return nil, h.Dialect.TranslateError(err)
+	}
+
+	return res, nil
+}
+
+// Query selects rows given by the sql statement
+// It requires the statement, the model to bind the statement, and the target bind model for the results
+// Outside of a transaction the rows are read from a replica, unless the context asks for the primary.
+func (h *SQLHandler) Query(ctx context.Context, qstmt string, model interface{}, bindModel interface{}) error {
+	defer h.logSlowQuery(qstmt, time.Now())
+
+	err := h.retry(ctx, true, func() error {
+		db, r := h.reader(ctx)
+
+		nstmt, err := db.PrepareNamedContext(ctx, qstmt)
+		if err == nil {
+			defer nstmt.Close()
+			err = nstmt.SelectContext(ctx, bindModel, model)
+		}
+		h.observe(r, err)
+
+		return err
+	})
+
+	return h.Dialect.TranslateError(err)
+}
+
+// QueryRow selects a row given by the sql statement
+// It requires the statement, the model to bind the statement, and the target bind model for the result
+// Outside of a transaction the row is read from a replica, unless the context asks for the primary.
+func (h *SQLHandler) QueryRow(ctx context.Context, qstmt string, model interface{}, bindModel interface{}) error {
+	defer h.logSlowQuery(qstmt, time.Now())
+
+	err := h.retry(ctx, true, func() error {
+		db, r := h.reader(ctx)
+
+		nstmt, err := db.PrepareNamedContext(ctx, qstmt)
+		if err == nil {
+			defer nstmt.Close()
+			err = nstmt.GetContext(ctx, bindModel, model)
+		}
+		h.observe(r, err)
+
+		return err
+	})
+
+	return h.Dialect.TranslateError(err)
+}
+
+// logSlowQuery logs the statement when it ran longer than the threshold, retries included
+func (h *SQLHandler) logSlowQuery(stmt string, start time.Time) {
+	if h.SlowQueryThreshold <= 0 {
+		return
+	}
+
+	if duration := time.Since(start); duration >= h.SlowQueryThreshold {
+		log.Printf("[DATABASE] slow query (%s): %s", duration, stmt)
+	}
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"gomora/infrastructures/database/types"
+)
+
+// unreachableConnector opens no connection, the pool settings are applied without any
+type unreachableConnector struct{}
+
+func (unreachableConnector) Connect(context.Context) (driver.Conn, error) {
+	return nil, errors.New("unreachable")
+}
+
+func (unreachableConnector) Driver() driver.Driver { return nil }
+
+func TestConfigurePool(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   types.PoolParams
+		expected int
+	}{
+		{name: "unbounded by default", params: types.PoolParams{}, expected: 0},
+		{name: "bounded open connections", params: types.PoolParams{MaxOpenConns: 50, MaxIdleConns: 10}, expected: 50},
+		{name: "negative open connections ignored", params: types.PoolParams{MaxOpenConns: -1}, expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := sqlx.NewDb(sql.OpenDB(unreachableConnector{}), "mysql")
+			defer db.Close()
+
+			ConfigurePool(db, test.params)
+
+			if maxOpenConns := db.Stats().MaxOpenConnections; maxOpenConns != test.expected {
+				t.Errorf("expected %d open connections at most, got %d", test.expected, maxOpenConns)
+			}
+		})
+	}
+}
+
+func TestLogSlowQuery(t *testing.T) {
+	var buf bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&buf)
+
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		duration  time.Duration
+		logged    bool
+	}{
+		{name: "disabled", threshold: 0, duration: time.Second, logged: false},
+		{name: "below the threshold", threshold: time.Hour, duration: time.Millisecond, logged: false},
+		{name: "above the threshold", threshold: time.Millisecond, duration: time.Second, logged: true},
+		{name: "at the threshold", threshold: time.Second, duration: time.Second, logged: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf.Reset()
+
+			h := &SQLHandler{SlowQueryThreshold: test.threshold}
+			h.logSlowQuery("SELECT 1", time.Now().Add(-test.duration))
+
+			if logged := strings.Contains(buf.String(), "[DATABASE] slow query") && strings.Contains(buf.String(), "SELECT 1"); logged != test.logged {
+				t.Errorf("expected logged %t, got %q", test.logged, buf.String())
+			}
+		})
+	}
+}
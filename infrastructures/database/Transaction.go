@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// transaction holds the transaction shared by the repositories of a unit of work
+type transaction struct {
+	tx         *sqlx.Tx
+	savepoints int
+	retriable  bool // a statement failed with a retriable error, the transaction must be replayed
+	committing bool // the unit of work is done, the failure is the commit's
+}
+
+// transactionKey scopes the transaction of the context to its handler
+type transactionKey struct {
+	handler *SQLHandler
+}
+
+// WithTransaction runs the unit of work in a transaction
+// The repositories called with the context given to fn execute their statements in the transaction.
+// It commits when fn returns nil and rolls back when fn returns an error or panics.
+// Nested calls run in a savepoint of the outer transaction.
+// The whole unit of work is replayed on deadlocks and transient errors, so fn must not have side effects
+// outside of the transaction unless WithoutRetry is set on the context. It is never replayed when the connection
+// was lost during the commit, the transaction may have been applied.
+func (h *SQLHandler) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if t, ok := h.transactionFromContext(ctx); ok {
+		return h.withSavepoint(ctx, t, fn)
+	}
+
+	return h.retryAttempts(ctx, func() (bool, error) {
+		t, err := h.runTransaction(ctx, fn)
+
+		return h.replayable(t, err), err
+	})
+}
+
+// replayable returns true when the failed attempt of the transaction was rolled back and is worth another one
+func (h *SQLHandler) replayable(t *transaction, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case t == nil:
+		// begin failed, nothing was sent
+		return h.Dialect.IsRetriable(err)
+	case t.committing:
+		// a commit that lost its connection may have been applied
+		return h.Dialect.IsRetriable(err) && !h.Dialect.IsConnectionError(err)
+	default:
+		// the repositories translate the driver errors, the transaction remembers whether one was retriable
+		return h.Dialect.IsRetriable(err) || t.retriable
+	}
+}
+
+// runTransaction runs one attempt of the unit of work
+func (h *SQLHandler) runTransaction(ctx context.Context, fn func(ctx context.Context) error) (t *transaction, err error) {
+	tx, err := h.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	t = &transaction{tx: tx}
+
+	err = fn(context.WithValue(ctx, transactionKey{h}, t))
+	if err != nil {
+		_ = tx.Rollback()
+		return t, err
+	}
+
+	t.committing = true
+
+	return t, tx.Commit()
+}
+
+// withSavepoint runs the nested unit of work in a savepoint of the transaction
+func (h *SQLHandler) withSavepoint(ctx context.Context, t *transaction, fn func(ctx context.Context) error) (err error) {
+	t.savepoints++
+	savepoint := fmt.Sprintf("sp_%d", t.savepoints)
+
+	if _, err := t.tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_, _ = t.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+			panic(p)
+		}
+	}()
+
+	err = fn(ctx)
+	if err != nil {
+		_, _ = t.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+		return err
+	}
+
+	_, err = t.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint)
+
+	return err
+}
+
+// transactionFromContext returns the transaction of the handler carried by the context
+func (h *SQLHandler) transactionFromContext(ctx context.Context) (*transaction, bool) {
+	t, ok := ctx.Value(transactionKey{h}).(*transaction)
+
+	return t, ok
+}
@@ -0,0 +1,37 @@
+package database
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrDuplicateEntry is wrapped by the handlers around the unique constraint violations of their driver
+var ErrDuplicateEntry = errors.New("duplicate entry")
+
+type primaryKey struct{}
+
+type noRetryKey struct{}
+
+// WithPrimary returns a copy of the context whose reads go to the primary
+// Use it to read your own writes, the replicas may lag behind.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// WithoutRetry returns a copy of the context whose statements are not retried
+// Use it for non-idempotent statements that may have been applied before the connection was lost.
+func WithoutRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryKey{}, true)
+}
+
+func primaryRequested(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+
+	return primary
+}
+
+func retryDisabled(ctx context.Context) bool {
+	noRetry, _ := ctx.Value(noRetryKey{}).(bool)
+
+	return noRetry
+}
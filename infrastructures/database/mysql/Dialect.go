@@ -0,0 +1,45 @@
+package mysql
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+
+	"gomora/infrastructures/database"
+)
+
+// mysql error numbers
+const (
+	errDuplicateEntry  = 1062
+	errLockWaitTimeout = 1205
+	errDeadlock        = 1213
+)
+
+// Dialect classifies the errors of the mysql driver
+type Dialect struct{}
+
+// IsConnectionError returns true when the statement failed to reach the server
+func (Dialect) IsConnectionError(err error) bool {
+	return database.IsConnectionError(err) || errors.Is(err, mysql.ErrInvalidConn)
+}
+
+// IsRetriable returns true for the deadlocks, lock wait timeouts and connection resets
+func (d Dialect) IsRetriable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == errDeadlock || mysqlErr.Number == errLockWaitTimeout
+	}
+
+	return d.IsConnectionError(err)
+}
+
+// TranslateError wraps the duplicate entries with database.ErrDuplicateEntry
+func (Dialect) TranslateError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errDuplicateEntry {
+		return fmt.Errorf("%w: %w", database.ErrDuplicateEntry, err)
+	}
+
+	return err
+}
@@ -0,0 +1,47 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"gomora/infrastructures/database"
+	"gomora/infrastructures/database/mysql/migrations"
+)
+
+const (
+	migrationLockName    = "gomora_schema_migrations"
+	migrationLockTimeout = 60 // seconds
+)
+
+// migrationDialect takes the GET_LOCK advisory lock and splits the migrations into statements
+type migrationDialect struct{}
+
+// NewMigrator returns the migrator of the embedded mysql migrations
+func NewMigrator(db *sqlx.DB) (*database.Migrator, error) {
+	return database.NewMigrator(db, migrationDialect{}, migrations.FS)
+}
+
+func (migrationDialect) Lock(ctx context.Context, conn *sql.Conn) error {
+	var acquired sql.NullInt64
+	err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", migrationLockName, migrationLockTimeout).Scan(&acquired)
+	if err != nil {
+		return err
+	}
+	if acquired.Int64 != 1 {
+		return database.ErrMigrationLocked
+	}
+
+	return nil
+}
+
+func (migrationDialect) Unlock(ctx context.Context, conn *sql.Conn) error {
+	var released sql.NullInt64
+
+	return conn.QueryRowContext(ctx, "SELECT RELEASE_LOCK(?)", migrationLockName).Scan(&released)
+}
+
+func (migrationDialect) Statements(body string) []string {
+	return database.SplitStatements(body)
+}
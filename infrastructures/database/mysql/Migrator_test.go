@@ -0,0 +1,30 @@
+package mysql
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"gomora/infrastructures/database"
+	"gomora/infrastructures/database/mysql/migrations"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	files, err := fs.Glob(migrations.FS, "*.up.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+
+	for _, up := range files {
+		for _, name := range []string{up, strings.TrimSuffix(up, ".up.sql") + ".down.sql"} {
+			body, err := fs.ReadFile(migrations.FS, name)
+			if err != nil {
+				t.Errorf("missing migration %s", name)
+				continue
+			}
+			if len(database.SplitStatements(string(body))) == 0 {
+				t.Errorf("migration %s has no statement", name)
+			}
+		}
+	}
+}
@@ -1,39 +1,24 @@
 package mysql
 
 import (
-	"context"
-	"database/sql"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net"
 	"os"
-	"time"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
-	"golang.org/x/crypto/ssh"
-	"golang.org/x/crypto/ssh/agent"
 
+	"gomora/infrastructures/database"
 	"gomora/infrastructures/database/mysql/types"
 )
 
 // MySQLDBHandler handles mysql operations
 type MySQLDBHandler struct {
-	Conn *sqlx.DB
-}
-
-type viaSSHDialer struct {
-	client *ssh.Client
-}
-
-// Begin starts a new transaction
-func (h *MySQLDBHandler) Begin() (*sqlx.Tx, error) {
-	// begin transaction
-	tx, err := h.Conn.Beginx()
-	if err != nil {
-		return nil, err
-	}
+	database.SQLHandler
 
-	return tx, nil
+	tunnel *sshTunnel
 }
 
 // Connect opens a new connection to the mysql interface
@@ -42,13 +27,38 @@ func (h *MySQLDBHandler) Connect(params types.ConnectionParams) error {
 		params.Dial = "tcp" // default
 	}
 
-	conn, err := sqlx.Connect("mysql", fmt.Sprintf("%s:%s@%s(%s:%s)/%s?parseTime=true&sql_mode=TRADITIONAL", params.DBUsername, params.DBPassword, params.Dial, params.DBHost, params.DBPort, params.DBDatabase))
+	config := mysql.NewConfig()
+	config.User = params.DBUsername
+	config.Passwd = params.DBPassword
+	config.Net = params.Dial
+	config.Addr = net.JoinHostPort(params.DBHost, params.DBPort)
+	config.DBName = params.DBDatabase
+	config.Timeout = params.Timeout
+	config.ReadTimeout = params.ReadTimeout
+	config.WriteTimeout = params.WriteTimeout
+
+	tlsConfig, err := registerTLSConfig(params)
 	if err != nil {
 		return err
 	}
+	config.TLSConfig = tlsConfig
+
+	// extra dsn options first, so that they can't unset the ones the repositories rely on
+	config.Params = map[string]string{}
+	for key, value := range params.Options {
+		config.Params[key] = value
+	}
 
-	conn.SetConnMaxLifetime(time.Minute * 4)
+	conn, err := sqlx.Connect("mysql", withRequiredOptions(config).FormatDSN())
+	if err != nil {
+		return err
+	}
+
+	database.ConfigurePool(conn, params.Pool)
 	h.Conn = conn
+	h.RetryPolicy = params.Retry
+	h.Dialect = Dialect{}
+	h.SlowQueryThreshold = params.SlowQueryThreshold
 
 	err = conn.Ping()
 	if err != nil {
@@ -57,103 +67,108 @@ func (h *MySQLDBHandler) Connect(params types.ConnectionParams) error {
 		return connErr
 	}
 
+	for _, dsn := range params.ReplicaDSNs {
+		replica, err := openReplica(dsn)
+		if err != nil {
+			return err
+		}
+		database.ConfigurePool(replica, params.Pool)
+
+		h.AddReplica(replica)
+	}
+
 	fmt.Println("[SERVER] Database connected successfully")
 
 	return nil
 }
 
-// ConnectViaSSH opens a new connection to the mysql interface through ssh
-// https://gist.github.com/vinzenz/d8e6834d9e25bbd422c14326f357cce0
-// https://unix.stackexchange.com/a/415266
+// ConnectViaSSH opens a new connection to the mysql interface through an ssh tunnel
+// The host key of the ssh server is verified against the known hosts, the tunnel is closed with the handler.
 func (h *MySQLDBHandler) ConnectViaSSH(paramsSSH types.SSHConnectionParams, params types.ConnectionParams) error {
-	var agentClient agent.Agent
-
-	// establish a connection to the local ssh-agent
-	conn, err := net.Dial("unix", os.Getenv("SSH_AUTH_SOCK"))
+	tunnel, dial, err := openSSHTunnel(paramsSSH)
 	if err != nil {
 		return err
 	}
-
-	// create a new instance of the ssh agent
-	agentClient = agent.NewClient(conn)
-
-	// the client configuration with configuration option to use the ssh-agent
-	sshConfig := &ssh.ClientConfig{
-		User:            paramsSSH.SSHUsername,
-		Auth:            []ssh.AuthMethod{},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-	}
-
-	// when the agentClient connection succeeded, add them as AuthMethod
-	if agentClient != nil {
-		sshConfig.Auth = append(sshConfig.Auth, ssh.PublicKeysCallback(agentClient.Signers))
-	}
-
-	// when there's a non empty password add the password AuthMethod
-	if paramsSSH.SSHPassword != "" {
-		sshConfig.Auth = append(sshConfig.Auth, ssh.PasswordCallback(func() (string, error) {
-			return paramsSSH.SSHPassword, nil
-		}))
-	}
-
-	// connect to the SSH Server
-	sshConn, err := ssh.Dial("tcp", fmt.Sprintf("%s:%s", paramsSSH.SSHHost, paramsSSH.SSHPort), sshConfig)
-	if err != nil {
-		return err
-	}
-
-	// now we register the ViaSSHDialer with the ssh connection as a parameter
-	mysql.RegisterDialContext(params.Dial, func(_ context.Context, addr string) (net.Conn, error) {
-		dialer := &viaSSHDialer{sshConn}
-		return dialer.Dial(addr)
-	})
+	params.Dial = dial
 
 	// connect to database
 	err = h.Connect(params)
 	if err != nil {
+		_ = tunnel.Close()
+
 		return err
 	}
+	h.tunnel = tunnel
 
 	return nil
 }
 
-// Execute executes the mysql statement following NamedExec
-// It requires a valid sql statement and its struct
-func (h *MySQLDBHandler) Execute(stmt string, model interface{}) (sql.Result, error) {
-	res, err := h.Conn.NamedExec(stmt, model)
-	if err != nil {
-		return nil, err
+// Close closes the connections, then the ssh tunnel if any
+func (h *MySQLDBHandler) Close() error {
+	err := h.SQLHandler.Close()
+	if h.tunnel != nil {
+		_ = h.tunnel.Close()
 	}
 
-	return res, nil
+	return err
 }
 
-// Query selects rows given by the sql statement
-// It requires the statement, the model to bind the statement, and the target bind model for the results
-func (h *MySQLDBHandler) Query(qstmt string, model interface{}, bindModel interface{}) error {
-	nstmt, err := h.Conn.PrepareNamed(qstmt)
+// openReplica opens the replica with the options the repositories rely on
+// The tls, timeouts and other options are read from the replica dsn.
+func openReplica(dsn string) (*sqlx.DB, error) {
+	config, err := mysql.ParseDSN(dsn)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("[SERVER] invalid replica dsn: %w", err)
 	}
-	defer nstmt.Close()
 
-	err = nstmt.Select(bindModel, model)
-	return err
+	return sqlx.Open("mysql", withRequiredOptions(config).FormatDSN())
 }
 
-// QueryRow selects a row given by the sql statement
-// It requires the statement, the model to bind the statement, and the target bind model for the result
-func (h *MySQLDBHandler) QueryRow(qstmt string, model interface{}, bindModel interface{}) error {
-	nstmt, err := h.Conn.PrepareNamed(qstmt)
-	if err != nil {
-		return err
+// withRequiredOptions sets the dsn options the repositories rely on
+func withRequiredOptions(config *mysql.Config) *mysql.Config {
+	config.ParseTime = true
+	if config.Params == nil {
+		config.Params = map[string]string{}
 	}
-	defer nstmt.Close()
+	config.Params["sql_mode"] = "TRADITIONAL"
 
-	err = nstmt.Get(bindModel, model)
-	return err
+	return config
 }
 
-func (v *viaSSHDialer) Dial(addr string) (net.Conn, error) {
-	return v.client.Dial("tcp", addr)
+// registerTLSConfig returns the tls option of the dsn
+// The ca, certificate and key files are only read with TLS set to "custom", registered under that name.
+func registerTLSConfig(params types.ConnectionParams) (string, error) {
+	switch params.TLS {
+	case "", "false":
+		return "", nil
+	case "true", "skip-verify", "preferred":
+		return params.TLS, nil
+	case "custom":
+		tlsConfig := &tls.Config{ServerName: params.DBHost, MinVersion: tls.VersionTLS12}
+
+		if len(params.TLSCAFile) > 0 {
+			ca, err := os.ReadFile(params.TLSCAFile)
+			if err != nil {
+				return "", err
+			}
+
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+				return "", fmt.Errorf("[SERVER] invalid tls ca %s", params.TLSCAFile)
+			}
+		}
+
+		if len(params.TLSCertFile) > 0 {
+			cert, err := tls.LoadX509KeyPair(params.TLSCertFile, params.TLSKeyFile)
+			if err != nil {
+				return "", err
+			}
+
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+
+		return params.TLS, mysql.RegisterTLSConfig(params.TLS, tlsConfig)
+	default:
+		return "", fmt.Errorf("[SERVER] unsupported tls mode %q", params.TLS)
+	}
 }
@@ -1,8 +1,18 @@
 package mysql
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/joho/godotenv"
 
@@ -10,10 +20,7 @@ import (
 )
 
 func TestConnection(t *testing.T) {
-	// load our environmental variables.
-	if err := godotenv.Load("../../../.env"); err != nil {
-		t.Error(err)
-	}
+	skipWithoutMySQL(t)
 
 	db := &MySQLDBHandler{}
 	err := db.Connect(types.ConnectionParams{
@@ -32,17 +39,20 @@ func TestConnection(t *testing.T) {
 }
 
 func TestSSHConnection(t *testing.T) {
-	// load our environmental variables.
-	if err := godotenv.Load("../../../.env"); err != nil {
-		t.Error(err)
+	skipWithoutMySQL(t)
+	if len(os.Getenv("DB_SSH_HOST")) == 0 {
+		t.Skip("no ssh tunnel configured")
 	}
 
 	db := &MySQLDBHandler{}
 	err := db.ConnectViaSSH(types.SSHConnectionParams{
-		SSHHost:     os.Getenv("DB_SSH_HOST"),
-		SSHPort:     os.Getenv("DB_SSH_PORT"),
-		SSHUsername: os.Getenv("DB_SSH_USER"),
-		SSHPassword: os.Getenv("DB_SSH_PASSWORD"),
+		SSHHost:           os.Getenv("DB_SSH_HOST"),
+		SSHPort:           os.Getenv("DB_SSH_PORT"),
+		SSHUsername:       os.Getenv("DB_SSH_USER"),
+		SSHPassword:       os.Getenv("DB_SSH_PASSWORD"),
+		SSHKeyFile:        os.Getenv("DB_SSH_KEY_FILE"),
+		SSHKeyPassphrase:  os.Getenv("DB_SSH_KEY_PASSPHRASE"),
+		SSHKnownHostsFile: os.Getenv("DB_SSH_KNOWN_HOSTS"),
 	}, types.ConnectionParams{
 		DBHost:     os.Getenv("DB_HOST"),
 		DBPort:     os.Getenv("DB_PORT"),
@@ -54,6 +64,93 @@ func TestSSHConnection(t *testing.T) {
 		t.Error("connection error via ssh")
 		return
 	}
+	defer db.Close()
 
 	t.Log("connection success via ssh")
 }
+
+func TestRegisterTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		return path
+	}
+
+	validCA := writeFile("ca.pem", selfSignedCA(t))
+	invalidCA := writeFile("invalid.pem", []byte("not a certificate"))
+	missing := filepath.Join(dir, "missing.pem")
+
+	tests := []struct {
+		name     string
+		params   types.ConnectionParams
+		expected string
+		err      string
+	}{
+		{name: "disabled", params: types.ConnectionParams{}, expected: ""},
+		{name: "explicitly disabled", params: types.ConnectionParams{TLS: "false"}, expected: ""},
+		{name: "verified", params: types.ConnectionParams{TLS: "true"}, expected: "true"},
+		{name: "skip verify", params: types.ConnectionParams{TLS: "skip-verify"}, expected: "skip-verify"},
+		{name: "preferred", params: types.ConnectionParams{TLS: "preferred"}, expected: "preferred"},
+		{name: "custom ca", params: types.ConnectionParams{TLS: "custom", DBHost: "db", TLSCAFile: validCA}, expected: "custom"},
+		{name: "custom without files", params: types.ConnectionParams{TLS: "custom", DBHost: "db"}, expected: "custom"},
+		{name: "invalid ca", params: types.ConnectionParams{TLS: "custom", TLSCAFile: invalidCA}, err: "invalid tls ca"},
+		{name: "missing ca", params: types.ConnectionParams{TLS: "custom", TLSCAFile: missing}, err: "no such file"},
+		{name: "missing key pair", params: types.ConnectionParams{TLS: "custom", TLSCertFile: missing, TLSKeyFile: missing}, err: "no such file"},
+		{name: "unsupported mode", params: types.ConnectionParams{TLS: "required"}, err: "unsupported tls mode"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tlsOption, err := registerTLSConfig(test.params)
+			if len(test.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected an error with %q, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil || tlsOption != test.expected {
+				t.Errorf("expected %q, got %q (%v)", test.expected, tlsOption, err)
+			}
+		})
+	}
+}
+
+// selfSignedCA returns the pem of a new self-signed ca certificate
+func selfSignedCA(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gomora test ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// skipWithoutMySQL loads our environmental variables and skips the test when no mysql server is configured
+func skipWithoutMySQL(t *testing.T) {
+	_ = godotenv.Load("../../../.env")
+
+	if driver := os.Getenv("DB_DRIVER"); len(os.Getenv("DB_HOST")) == 0 || (len(driver) > 0 && driver != "mysql") {
+		t.Skip("no mysql server configured")
+	}
+}
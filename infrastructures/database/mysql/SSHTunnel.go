@@ -0,0 +1,303 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/segmentio/ksuid"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"gomora/infrastructures/database/mysql/types"
+)
+
+// defaultSSHKeepAlive is the interval of the keepalive requests sent to the ssh server
+const defaultSSHKeepAlive = time.Second * 30
+
+var (
+	// errTunnelClosed is returned when dialing through a closed tunnel
+	errTunnelClosed = errors.New("[SERVER] ssh tunnel closed")
+	// errKeepAliveTimeout is returned when the ssh server doesn't answer a keepalive within the interval
+	errKeepAliveTimeout = errors.New("[SERVER] ssh keepalive timed out")
+)
+
+// tunnels holds the open tunnels by dial name
+// The driver keeps the registered dial functions forever, so they look the tunnel up and fail once it is closed.
+var tunnels sync.Map
+
+// sshTunnel forwards the database connections through an ssh server
+// The ssh connection is checked with keepalives and reestablished when it is lost.
+type sshTunnel struct {
+	addr      string
+	config    *ssh.ClientConfig
+	keepAlive time.Duration
+
+	dialMu sync.Mutex // a single ssh dial at a time, held without mu so that Close and the callers don't wait on it
+	mu     sync.Mutex
+	client *ssh.Client
+	closed bool
+	done   chan struct{}
+}
+
+// openSSHTunnel connects to the ssh server and registers the tunnel as a driver dial
+func openSSHTunnel(params types.SSHConnectionParams) (*sshTunnel, string, error) {
+	config, err := sshClientConfig(params)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if params.SSHKeepAlive <= 0 {
+		params.SSHKeepAlive = defaultSSHKeepAlive
+	}
+
+	t := &sshTunnel{
+		addr:      net.JoinHostPort(params.SSHHost, params.SSHPort),
+		config:    config,
+		keepAlive: params.SSHKeepAlive,
+		done:      make(chan struct{}),
+	}
+	if _, err := t.connect(); err != nil {
+		return nil, "", err
+	}
+	go t.keepAliveLoop()
+
+	dial := "ssh+" + ksuid.New().String()
+	tunnels.Store(dial, t)
+	mysql.RegisterDialContext(dial, func(ctx context.Context, addr string) (net.Conn, error) {
+		t, ok := tunnels.Load(dial)
+		if !ok {
+			return nil, errTunnelClosed
+		}
+
+		return t.(*sshTunnel).DialContext(ctx, addr)
+	})
+
+	return t, dial, nil
+}
+
+// sshClientConfig builds the client configuration verifying the host key against the known hosts
+// The private key file, the ssh-agent when SSH_AUTH_SOCK is set, and the password are tried in that order.
+func sshClientConfig(params types.SSHConnectionParams) (*ssh.ClientConfig, error) {
+	knownHostsFile := params.SSHKnownHostsFile
+	if len(knownHostsFile) == 0 {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		knownHostsFile = filepath.Join(home, ".ssh", "known_hosts")
+	}
+
+	hostKeyCallback, err := knownhosts.New(knownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("[SERVER] unable to read the ssh known hosts: %w", err)
+	}
+
+	config := &ssh.ClientConfig{
+		User:            params.SSHUsername,
+		Auth:            []ssh.AuthMethod{},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         time.Second * 10,
+	}
+
+	if len(params.SSHKeyFile) > 0 {
+		key, err := os.ReadFile(params.SSHKeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		var signer ssh.Signer
+		if len(params.SSHKeyPassphrase) > 0 {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(key, []byte(params.SSHKeyPassphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey(key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("[SERVER] invalid ssh key %s: %w", params.SSHKeyFile, err)
+		}
+
+		config.Auth = append(config.Auth, ssh.PublicKeys(signer))
+	}
+
+	if socket := os.Getenv("SSH_AUTH_SOCK"); len(socket) > 0 {
+		// a missing agent is not fatal as long as another method is configured
+		if conn, err := net.Dial("unix", socket); err == nil {
+			config.Auth = append(config.Auth, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		} else {
+			log.Printf("[SERVER] ssh-agent unavailable: %s", err)
+		}
+	}
+
+	if len(params.SSHPassword) > 0 {
+		config.Auth = append(config.Auth, ssh.Password(params.SSHPassword))
+	}
+
+	if len(config.Auth) == 0 {
+		return nil, errors.New("[SERVER] no ssh authentication method, set a key file, an ssh-agent or a password")
+	}
+
+	return config, nil
+}
+
+// DialContext opens a connection to the address through the tunnel
+// A dial failing on a stale ssh connection is retried once on a new one.
+func (t *sshTunnel) DialContext(ctx context.Context, addr string) (net.Conn, error) {
+	client, err := t.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := client.DialContext(ctx, "tcp", addr)
+	if err != nil && ctx.Err() == nil {
+		t.reset(client)
+		if client, err = t.connect(); err != nil {
+			return nil, err
+		}
+		conn, err = client.DialContext(ctx, "tcp", addr)
+	}
+
+	return conn, err
+}
+
+// Close stops the keepalives and closes the ssh connection
+func (t *sshTunnel) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	close(t.done)
+
+	tunnels.Range(func(dial, tunnel any) bool {
+		if tunnel == t {
+			tunnels.Delete(dial)
+		}
+
+		return true
+	})
+
+	if t.client == nil {
+		return nil
+	}
+
+	return t.client.Close()
+}
+
+// connect returns the current ssh connection, dialing a new one when there is none
+// The callers arriving during a dial wait for it and share its connection.
+func (t *sshTunnel) connect() (*ssh.Client, error) {
+	if client, err := t.current(); client != nil || err != nil {
+		return client, err
+	}
+
+	t.dialMu.Lock()
+	defer t.dialMu.Unlock()
+
+	if client, err := t.current(); client != nil || err != nil {
+		return client, err
+	}
+
+	client, err := ssh.Dial("tcp", t.addr, t.config)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		_ = client.Close()
+
+		return nil, errTunnelClosed
+	}
+	t.client = client
+	t.mu.Unlock()
+
+	// drop the connection as soon as the server closes it
+	go func() {
+		_ = client.Wait()
+		t.reset(client)
+	}()
+
+	return client, nil
+}
+
+// current returns the current ssh connection, nil when there is none
+func (t *sshTunnel) current() (*ssh.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return nil, errTunnelClosed
+	}
+
+	return t.client, nil
+}
+
+// reset closes the ssh connection unless it was already replaced
+func (t *sshTunnel) reset(client *ssh.Client) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client == client {
+		t.client = nil
+	}
+	_ = client.Close()
+}
+
+// keepAliveLoop sends the keepalives and reconnects the tunnel when they fail
+func (t *sshTunnel) keepAliveLoop() {
+	ticker := time.NewTicker(t.keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-ticker.C:
+		}
+
+		client, _ := t.current()
+		if client != nil {
+			err := t.sendKeepAlive(client)
+			if err == nil {
+				continue
+			}
+			log.Printf("[SERVER] ssh tunnel to %s lost, reconnecting: %s", t.addr, err)
+			t.reset(client)
+		}
+
+		if _, err := t.connect(); err != nil && !errors.Is(err, errTunnelClosed) {
+			log.Printf("[SERVER] ssh tunnel to %s: %s", t.addr, err)
+		}
+	}
+}
+
+// sendKeepAlive sends a keepalive and waits for its answer up to the keepalive interval
+// A server that stopped answering would block the request forever, closing the connection ends it.
+func (t *sshTunnel) sendKeepAlive(client *ssh.Client) error {
+	errc := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		errc <- err
+	}()
+
+	timer := time.NewTimer(t.keepAlive)
+	defer timer.Stop()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-timer.C:
+		return errKeepAliveTimeout
+	}
+}
@@ -0,0 +1,347 @@
+package mysql
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"gomora/infrastructures/database/mysql/types"
+)
+
+// sshServer is an in-process ssh server forwarding the direct-tcpip channels
+type sshServer struct {
+	listener       net.Listener
+	hostKey        ssh.Signer
+	config         *ssh.ServerConfig
+	stallKeepAlive atomic.Bool // leave the keepalives unanswered
+	accepted       atomic.Int32
+
+	mu    sync.Mutex
+	conns []*ssh.ServerConn
+}
+
+// newSSHServer starts an ssh server accepting the client key and the password
+func newSSHServer(t *testing.T, clientKey ssh.PublicKey, password string) *sshServer {
+	t.Helper()
+
+	_, hostKey, _ := ed25519.GenerateKey(rand.Reader)
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &sshServer{hostKey: hostSigner}
+	server.config = &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if clientKey != nil && string(key.Marshal()) == string(clientKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, io.EOF
+		},
+		PasswordCallback: func(conn ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if len(password) > 0 && string(pass) == password {
+				return nil, nil
+			}
+			return nil, io.EOF
+		},
+	}
+	server.config.AddHostKey(hostSigner)
+
+	server.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		server.listener.Close()
+		server.dropConnections()
+	})
+
+	go server.serve()
+
+	return server
+}
+
+func (server *sshServer) serve() {
+	for {
+		conn, err := server.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			sshConn, channels, requests, err := ssh.NewServerConn(conn, server.config)
+			if err != nil {
+				conn.Close()
+				return
+			}
+			server.accepted.Add(1)
+
+			server.mu.Lock()
+			server.conns = append(server.conns, sshConn)
+			server.mu.Unlock()
+
+			go func() {
+				for request := range requests {
+					if !server.stallKeepAlive.Load() {
+						_ = request.Reply(true, nil)
+					}
+				}
+			}()
+
+			for channel := range channels {
+				go forward(channel)
+			}
+		}()
+	}
+}
+
+// dropConnections closes the ssh connections from the server side
+func (server *sshServer) dropConnections() {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	for _, conn := range server.conns {
+		conn.Close()
+	}
+	server.conns = nil
+}
+
+// knownHosts writes a known hosts file holding the key for the server address
+func (server *sshServer) knownHosts(t *testing.T, key ssh.PublicKey) string {
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(server.listener.Addr().String())}, key)
+	if err := os.WriteFile(path, []byte(line+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// params returns the tunnel params of the server
+func (server *sshServer) params(knownHostsFile string) types.SSHConnectionParams {
+	host, port, _ := net.SplitHostPort(server.listener.Addr().String())
+
+	return types.SSHConnectionParams{
+		SSHHost:           host,
+		SSHPort:           port,
+		SSHUsername:       "gomora",
+		SSHKnownHostsFile: knownHostsFile,
+	}
+}
+
+// forward connects the direct-tcpip channel to its destination
+func forward(newChannel ssh.NewChannel) {
+	var payload struct {
+		Host       string
+		Port       uint32
+		OriginHost string
+		OriginPort uint32
+	}
+	if newChannel.ChannelType() != "direct-tcpip" || ssh.Unmarshal(newChannel.ExtraData(), &payload) != nil {
+		_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported")
+		return
+	}
+
+	conn, err := net.Dial("tcp", net.JoinHostPort(payload.Host, fmt.Sprint(payload.Port)))
+	if err != nil {
+		_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	go func() {
+		_, _ = io.Copy(channel, conn)
+		channel.Close()
+	}()
+	_, _ = io.Copy(conn, channel)
+	conn.Close()
+}
+
+// echoServer starts a tcp server echoing its connections
+func echoServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, _ = io.Copy(conn, conn)
+				conn.Close()
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// echo sends a message through the tunnel and checks it comes back
+func echo(t *testing.T, tunnel *sshTunnel, addr string) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	conn, err := tunnel.DialContext(ctx, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "ping" {
+		t.Fatalf("expected ping, got %q (%v)", buf, err)
+	}
+}
+
+// eventually waits for the condition to hold
+func eventually(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	for deadline := time.Now().Add(time.Second * 5); time.Now().Before(deadline); time.Sleep(time.Millisecond * 10) {
+		if condition() {
+			return
+		}
+	}
+	t.Fatal("condition not met in time")
+}
+
+func TestSSHClientConfig(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(knownHosts, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// a missing known hosts file is an error, not an insecure fallback
+	if _, err := sshClientConfig(types.SSHConnectionParams{SSHPassword: "secret", SSHKnownHostsFile: knownHosts + ".missing"}); err == nil {
+		t.Error("expected an error without known hosts")
+	}
+
+	if _, err := sshClientConfig(types.SSHConnectionParams{SSHKnownHostsFile: knownHosts}); err == nil {
+		t.Error("expected an error without authentication method")
+	}
+
+	// the password alone is enough without an ssh-agent
+	config, err := sshClientConfig(types.SSHConnectionParams{SSHUsername: "gomora", SSHPassword: "secret", SSHKnownHostsFile: knownHosts})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.User != "gomora" || len(config.Auth) != 1 {
+		t.Errorf("unexpected config %+v", config)
+	}
+}
+
+func TestSSHTunnelHostKeyMismatch(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	server := newSSHServer(t, nil, "secret")
+
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+	otherSigner, _ := ssh.NewSignerFromKey(otherKey)
+
+	params := server.params(server.knownHosts(t, otherSigner.PublicKey()))
+	params.SSHPassword = "secret"
+
+	if _, _, err := openSSHTunnel(params); err == nil || !strings.Contains(err.Error(), "key mismatch") {
+		t.Fatalf("expected a key mismatch, got %v", err)
+	}
+}
+
+func TestSSHTunnelKeyPassphrase(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	_, clientKey, _ := ed25519.GenerateKey(rand.Reader)
+	clientSigner, _ := ssh.NewSignerFromKey(clientKey)
+	block, err := ssh.MarshalPrivateKeyWithPassphrase(clientKey, "gomora", []byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	server := newSSHServer(t, clientSigner.PublicKey(), "")
+	params := server.params(server.knownHosts(t, server.hostKey.PublicKey()))
+	params.SSHKeyFile = keyFile
+
+	// the key can't be read without its passphrase
+	if _, _, err := openSSHTunnel(params); err == nil || !strings.Contains(err.Error(), "invalid ssh key") {
+		t.Fatalf("expected an invalid key, got %v", err)
+	}
+
+	params.SSHKeyPassphrase = "passphrase"
+	tunnel, _, err := openSSHTunnel(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tunnel.Close()
+
+	echo(t, tunnel, echoServer(t))
+}
+
+func TestSSHTunnelReconnect(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	server := newSSHServer(t, nil, "secret")
+	addr := echoServer(t)
+
+	params := server.params(server.knownHosts(t, server.hostKey.PublicKey()))
+	params.SSHPassword = "secret"
+	params.SSHKeepAlive = time.Millisecond * 50
+
+	tunnel, _, err := openSSHTunnel(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tunnel.Close()
+	echo(t, tunnel, addr)
+
+	// the connection dropped by the server is replaced
+	server.dropConnections()
+	eventually(t, func() bool { return server.accepted.Load() >= 2 })
+	echo(t, tunnel, addr)
+
+	// so is the connection whose keepalives are no longer answered
+	server.stallKeepAlive.Store(true)
+	eventually(t, func() bool { return server.accepted.Load() >= 3 })
+	server.stallKeepAlive.Store(false)
+	echo(t, tunnel, addr)
+
+	// the closed tunnel no longer dials
+	if err := tunnel.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tunnel.DialContext(context.Background(), addr); err != errTunnelClosed {
+		t.Errorf("expected %v, got %v", errTunnelClosed, err)
+	}
+}
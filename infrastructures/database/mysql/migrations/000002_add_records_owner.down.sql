@@ -0,0 +1,3 @@
+ALTER TABLE
+    `records` DROP INDEX `records_owner_id_index`,
+    DROP COLUMN `owner_id`;
@@ -0,0 +1,6 @@
+ALTER TABLE
+    `records`
+ADD
+    COLUMN `owner_id` varchar(255) NOT NULL DEFAULT '' AFTER `id`,
+ADD
+    INDEX `records_owner_id_index` (`owner_id`);
@@ -0,0 +1,8 @@
+ALTER TABLE
+    `records` DROP INDEX `records_tenant_id_owner_id_index`,
+ADD
+    INDEX `records_owner_id_index` (`owner_id`),
+    DROP PRIMARY KEY,
+ADD
+    PRIMARY KEY (`id`),
+    DROP COLUMN `tenant_id`;
@@ -0,0 +1,10 @@
+ALTER TABLE
+    `records`
+ADD
+    COLUMN `tenant_id` varchar(64) NOT NULL DEFAULT 'default' FIRST,
+    DROP PRIMARY KEY,
+ADD
+    PRIMARY KEY (`tenant_id`, `id`),
+    DROP INDEX `records_owner_id_index`,
+ADD
+    INDEX `records_tenant_id_owner_id_index` (`tenant_id`, `owner_id`);
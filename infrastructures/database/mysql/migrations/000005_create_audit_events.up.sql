@@ -0,0 +1,22 @@
+CREATE TABLE
+    `audit_events` (
+        `id` varchar(255) NOT NULL,
+        `occurred_at` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+        `actor` varchar(255) NOT NULL DEFAULT '',
+        `tenant_id` varchar(64) NOT NULL DEFAULT '',
+        `ip` varchar(45) NOT NULL DEFAULT '',
+        `request_id` varchar(255) NOT NULL DEFAULT '',
+        `action` varchar(64) NOT NULL,
+        `resource_id` varchar(255) NOT NULL DEFAULT '',
+        `outcome` varchar(16) NOT NULL,
+        `error_code` varchar(64) NOT NULL DEFAULT '',
+        PRIMARY KEY (`id`),
+        INDEX `audit_events_actor_index` (`actor`),
+        INDEX `audit_events_action_index` (`action`),
+        INDEX `audit_events_resource_id_index` (`resource_id`),
+        INDEX `audit_events_occurred_at_index` (`occurred_at`)
+    ) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci;
+
+CREATE TRIGGER `audit_events_prevent_update` BEFORE UPDATE ON `audit_events` FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_events is append-only';
+
+CREATE TRIGGER `audit_events_prevent_delete` BEFORE DELETE ON `audit_events` FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_events is append-only';
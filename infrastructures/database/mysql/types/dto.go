@@ -1,5 +1,11 @@
 package types
 
+import (
+	"time"
+
+	"gomora/infrastructures/database/types"
+)
+
 type ConnectionParams struct {
 	Dial       string // register dial
 	DBHost     string
@@ -7,6 +13,22 @@ type ConnectionParams struct {
 	DBDatabase string
 	DBUsername string
 	DBPassword string
+	Retry      types.RetryPolicy
+	Pool       types.PoolParams
+
+	TLS         string // false, true, skip-verify or custom
+	TLSCAFile   string // custom only
+	TLSCertFile string // custom only, client certificate
+	TLSKeyFile  string // custom only, client key
+
+	Timeout      time.Duration // dial timeout
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	Options      map[string]string // extra dsn options, like charset or loc
+
+	SlowQueryThreshold time.Duration
+
+	ReplicaDSNs []string // user:password@tcp(host:port)/database of the read replicas
 }
 
 type SSHConnectionParams struct {
@@ -14,4 +36,9 @@ type SSHConnectionParams struct {
 	SSHPort     string
 	SSHUsername string
 	SSHPassword string
+
+	SSHKeyFile        string // private key, tried before the ssh-agent and the password
+	SSHKeyPassphrase  string
+	SSHKnownHostsFile string        // defaults to ~/.ssh/known_hosts
+	SSHKeepAlive      time.Duration // defaults to 30s
 }
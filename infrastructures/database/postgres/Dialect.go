@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"gomora/infrastructures/database"
+)
+
+// postgres error codes
+const (
+	errUniqueViolation      pq.ErrorCode = "23505"
+	errSerializationFailure pq.ErrorCode = "40001"
+	errDeadlockDetected     pq.ErrorCode = "40P01"
+)
+
+// Dialect classifies the errors of the postgres driver
+type Dialect struct{}
+
+// IsConnectionError returns true when the statement failed to reach the server
+func (Dialect) IsConnectionError(err error) bool {
+	return database.IsConnectionError(err)
+}
+
+// IsRetriable returns true for the deadlocks, serialization failures and connection resets
+func (Dialect) IsRetriable(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == errSerializationFailure || pqErr.Code == errDeadlockDetected
+	}
+
+	return database.IsConnectionError(err)
+}
+
+// TranslateError wraps the unique violations with database.ErrDuplicateEntry
+func (Dialect) TranslateError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == errUniqueViolation {
+		return fmt.Errorf("%w: %w", database.ErrDuplicateEntry, err)
+	}
+
+	return err
+}
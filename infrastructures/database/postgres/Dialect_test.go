@@ -0,0 +1,28 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+
+	"gomora/infrastructures/database"
+)
+
+func TestDialect(t *testing.T) {
+	dialect := Dialect{}
+
+	if err := dialect.TranslateError(&pq.Error{Code: errUniqueViolation}); !errors.Is(err, database.ErrDuplicateEntry) {
+		t.Errorf("expected a duplicate entry, got %v", err)
+	}
+	if err := dialect.TranslateError(nil); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+
+	if !dialect.IsRetriable(&pq.Error{Code: errDeadlockDetected}) || !dialect.IsRetriable(&pq.Error{Code: errSerializationFailure}) {
+		t.Error("expected the deadlocks and serialization failures to be retriable")
+	}
+	if dialect.IsRetriable(&pq.Error{Code: errUniqueViolation}) {
+		t.Error("expected the unique violations not to be retriable")
+	}
+}
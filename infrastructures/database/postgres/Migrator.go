@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"gomora/infrastructures/database"
+	"gomora/infrastructures/database/postgres/migrations"
+)
+
+// migrationLockID is the key of the advisory lock of the migrations
+const migrationLockID = 7238591064
+
+// migrationDialect takes the pg_advisory_lock and runs each migration as a whole
+type migrationDialect struct{}
+
+// NewMigrator returns the migrator of the embedded postgres migrations
+func NewMigrator(db *sqlx.DB) (*database.Migrator, error) {
+	return database.NewMigrator(db, migrationDialect{}, migrations.FS)
+}
+
+func (migrationDialect) Lock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockID)
+
+	return err
+}
+
+func (migrationDialect) Unlock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	return err
+}
+
+// Statements keeps the migration whole, postgres runs the statements of a query together
+// and the plpgsql bodies contain semicolons.
+func (migrationDialect) Statements(body string) []string {
+	return []string{body}
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // postgres driver
+
+	"gomora/infrastructures/database"
+	"gomora/infrastructures/database/postgres/types"
+)
+
+// PostgresDBHandler handles postgres operations
+// The named statements of the repositories are rebound to the $N placeholders of postgres.
+type PostgresDBHandler struct {
+	database.SQLHandler
+}
+
+// Connect opens a new connection to the postgres interface
+func (h *PostgresDBHandler) Connect(params types.ConnectionParams) error {
+	if len(params.SSLMode) == 0 {
+		params.SSLMode = "disable" // default
+	}
+
+	options := url.Values{}
+	for key, value := range params.Options {
+		options.Set(key, value)
+	}
+	options.Set("sslmode", params.SSLMode)
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(params.DBUsername, params.DBPassword),
+		Host:     net.JoinHostPort(params.DBHost, params.DBPort),
+		Path:     params.DBDatabase,
+		RawQuery: options.Encode(),
+	}
+
+	conn, err := sqlx.Connect("postgres", dsn.String())
+	if err != nil {
+		return err
+	}
+
+	database.ConfigurePool(conn, params.Pool)
+	h.Conn = conn
+	h.RetryPolicy = params.Retry
+	h.Dialect = Dialect{}
+	h.SlowQueryThreshold = params.SlowQueryThreshold
+
+	err = conn.Ping()
+	if err != nil {
+		connErr := fmt.Errorf("[SERVER] Error connecting to the database! %s", err.Error())
+
+		return connErr
+	}
+
+	for _, dsn := range params.ReplicaDSNs {
+		replica, err := sqlx.Open("postgres", dsn)
+		if err != nil {
+			return fmt.Errorf("[SERVER] invalid replica dsn: %w", err)
+		}
+		database.ConfigurePool(replica, params.Pool)
+
+		h.AddReplica(replica)
+	}
+
+	fmt.Println("[SERVER] Database connected successfully")
+
+	return nil
+}
@@ -0,0 +1,6 @@
+ALTER TABLE
+    records
+ADD
+    COLUMN owner_id varchar(255) NOT NULL DEFAULT '';
+
+CREATE INDEX records_owner_id_index ON records (owner_id);
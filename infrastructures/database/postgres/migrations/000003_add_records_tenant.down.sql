@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS records_tenant_id_owner_id_index;
+
+CREATE INDEX records_owner_id_index ON records (owner_id);
+
+ALTER TABLE
+    records DROP CONSTRAINT records_pkey,
+ADD
+    PRIMARY KEY (id),
+    DROP COLUMN tenant_id;
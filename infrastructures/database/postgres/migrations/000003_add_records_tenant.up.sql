@@ -0,0 +1,11 @@
+ALTER TABLE
+    records
+ADD
+    COLUMN tenant_id varchar(64) NOT NULL DEFAULT 'default',
+    DROP CONSTRAINT records_pkey,
+ADD
+    PRIMARY KEY (tenant_id, id);
+
+DROP INDEX IF EXISTS records_owner_id_index;
+
+CREATE INDEX records_tenant_id_owner_id_index ON records (tenant_id, owner_id);
@@ -0,0 +1,15 @@
+CREATE TABLE
+    api_keys (
+        id varchar(255) NOT NULL,
+        prefix varchar(255) NOT NULL,
+        key_hash char(64) NOT NULL,
+        name varchar(255) NOT NULL,
+        subject varchar(255) NOT NULL,
+        tenant_id varchar(64) NOT NULL DEFAULT '',
+        scopes varchar(1024) NOT NULL DEFAULT '',
+        allowed_ips varchar(1024) NOT NULL DEFAULT '',
+        expires_at timestamptz NULL DEFAULT NULL,
+        revoked_at timestamptz NULL DEFAULT NULL,
+        created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (id)
+    );
@@ -0,0 +1,32 @@
+CREATE TABLE
+    audit_events (
+        id varchar(255) NOT NULL,
+        occurred_at timestamptz(6) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        actor varchar(255) NOT NULL DEFAULT '',
+        tenant_id varchar(64) NOT NULL DEFAULT '',
+        ip varchar(45) NOT NULL DEFAULT '',
+        request_id varchar(255) NOT NULL DEFAULT '',
+        action varchar(64) NOT NULL,
+        resource_id varchar(255) NOT NULL DEFAULT '',
+        outcome varchar(16) NOT NULL,
+        error_code varchar(64) NOT NULL DEFAULT '',
+        PRIMARY KEY (id)
+    );
+
+CREATE INDEX audit_events_actor_index ON audit_events (actor);
+
+CREATE INDEX audit_events_action_index ON audit_events (action);
+
+CREATE INDEX audit_events_resource_id_index ON audit_events (resource_id);
+
+CREATE INDEX audit_events_occurred_at_index ON audit_events (occurred_at);
+
+CREATE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_prevent_update BEFORE UPDATE ON audit_events FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+CREATE TRIGGER audit_events_prevent_delete BEFORE DELETE ON audit_events FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
@@ -0,0 +1,13 @@
+package types
+
+import (
+	"gomora/infrastructures/database/types"
+)
+
+// PostgresDBHandlerInterface contains the implementable methods for the Postgres DB handler
+type PostgresDBHandlerInterface interface {
+	types.DBHandlerInterface
+
+	// Connect opens a new connection to the postgres interface
+	Connect(params ConnectionParams) error
+}
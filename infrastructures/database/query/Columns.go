@@ -0,0 +1,54 @@
+package query
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Model is implemented by the entities stored in a table
+type Model interface {
+	// GetModelName returns the table of the entity
+	GetModelName() string
+}
+
+// Columns returns the columns of the model, in the order of its fields
+// The names follow the mapping of sqlx: the db tag when set, the lowercase field name otherwise.
+// Fields tagged db:"-" are skipped and untagged embedded structs are flattened.
+func Columns(model interface{}) []string {
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return columns(t)
+}
+
+func columns(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("db"), ",")
+		if name == "-" {
+			continue
+		}
+
+		// the fields of embedded structs are promoted, even when the struct type is unexported
+		if len(name) == 0 && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			names = append(names, columns(field.Type)...)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if len(name) == 0 {
+			name = sqlx.NameMapper(field.Name)
+		}
+		names = append(names, name)
+	}
+
+	return names
+}
@@ -0,0 +1,48 @@
+package query
+
+// Condition is a comparison of a column with a value, bound as a named parameter
+type Condition struct {
+	column   string
+	operator string
+	value    interface{}
+}
+
+// Eq matches the rows whose column equals the value
+func Eq(column string, value interface{}) Condition {
+	return Condition{column, "=", value}
+}
+
+// NotEq matches the rows whose column differs from the value
+func NotEq(column string, value interface{}) Condition {
+	return Condition{column, "<>", value}
+}
+
+// Lt matches the rows whose column is lower than the value
+func Lt(column string, value interface{}) Condition {
+	return Condition{column, "<", value}
+}
+
+// Lte matches the rows whose column is lower than or equal to the value
+func Lte(column string, value interface{}) Condition {
+	return Condition{column, "<=", value}
+}
+
+// Gt matches the rows whose column is greater than the value
+func Gt(column string, value interface{}) Condition {
+	return Condition{column, ">", value}
+}
+
+// Gte matches the rows whose column is greater than or equal to the value
+func Gte(column string, value interface{}) Condition {
+	return Condition{column, ">=", value}
+}
+
+// Order is the sort direction of a column
+type Order string
+
+const (
+	// Asc sorts from the lowest value
+	Asc Order = "ASC"
+	// Desc sorts from the highest value
+	Desc Order = "DESC"
+)
@@ -0,0 +1,61 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InsertBuilder builds an insert statement of the columns of the model
+type InsertBuilder struct {
+	table   string
+	model   Model
+	columns []string
+	err     error
+}
+
+// Insert starts an insert statement of the model into its table
+func Insert(model Model) *InsertBuilder {
+	return &InsertBuilder{
+		table:   model.GetModelName(),
+		model:   model,
+		columns: Columns(model),
+	}
+}
+
+// Omit leaves the columns filled by the database out, like the creation timestamp
+func (b *InsertBuilder) Omit(columns ...string) *InsertBuilder {
+	for _, column := range columns {
+		kept := b.columns[:0:0]
+		for _, c := range b.columns {
+			if c != column {
+				kept = append(kept, c)
+			}
+		}
+
+		if len(kept) == len(b.columns) && b.err == nil {
+			b.err = fmt.Errorf("query: unknown column %q of %s", column, b.table)
+		}
+		b.columns = kept
+	}
+
+	return b
+}
+
+// Build returns the named statement, bound to the model by the database handlers
+func (b *InsertBuilder) Build() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (:%s)", b.table, strings.Join(b.columns, ", "), strings.Join(b.columns, ", :")), nil
+}
+
+// SQL returns the statement with the positional placeholders of the bind type, like sqlx.DOLLAR, and its arguments
+func (b *InsertBuilder) SQL(bindType int) (string, []interface{}, error) {
+	stmt, err := b.Build()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return positional(bindType, stmt, b.model)
+}
@@ -0,0 +1,177 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SelectBuilder builds a select statement listing the columns of the model
+// The columns of the conditions and orders are checked against the model, so that a typo fails before reaching the database.
+type SelectBuilder struct {
+	table   string
+	columns []string
+	known   map[string]bool
+
+	where  []string
+	keys   []string
+	sorts  []Order
+	params map[string]interface{}
+	limit  int
+	err    error
+}
+
+// Select starts a select statement of the columns of the model from its table
+func Select(model Model) *SelectBuilder {
+	columns := Columns(model)
+	known := map[string]bool{}
+	for _, column := range columns {
+		known[column] = true
+	}
+
+	return &SelectBuilder{
+		table:   model.GetModelName(),
+		columns: columns,
+		known:   known,
+		params:  map[string]interface{}{},
+	}
+}
+
+// Where adds the conditions, all of them must match
+func (b *SelectBuilder) Where(conditions ...Condition) *SelectBuilder {
+	for _, condition := range conditions {
+		if !b.check(condition.column) {
+			return b
+		}
+
+		b.where = append(b.where, fmt.Sprintf("%s%s:%s", condition.column, condition.operator, b.bind(condition.value)))
+	}
+
+	return b
+}
+
+// OrderBy sorts the rows by the column, after the previous orders
+func (b *SelectBuilder) OrderBy(column string, order Order) *SelectBuilder {
+	if !b.check(column) {
+		return b
+	}
+	if order != Asc && order != Desc {
+		b.err = fmt.Errorf("query: unknown order %q", order)
+		return b
+	}
+
+	b.keys = append(b.keys, column)
+	b.sorts = append(b.sorts, order)
+
+	return b
+}
+
+// After skips the rows up to the cursor, for keyset pagination
+// The cursor holds the values of the order columns of the last row of the previous page, in the same order.
+func (b *SelectBuilder) After(cursor ...interface{}) *SelectBuilder {
+	if b.err != nil {
+		return b
+	}
+	if len(cursor) != len(b.keys) {
+		b.err = fmt.Errorf("query: the cursor has %d values for %d order columns", len(cursor), len(b.keys))
+		return b
+	}
+
+	// (a > :a) OR (a = :a AND b > :b) ..., which works across the dialects unlike the row comparisons
+	var alternatives []string
+	var equals []string
+	for i, value := range cursor {
+		param := b.bind(value)
+
+		operator := ">"
+		if b.sorts[i] == Desc {
+			operator = "<"
+		}
+
+		comparison := append(append([]string{}, equals...), fmt.Sprintf("%s%s:%s", b.keys[i], operator, param))
+		alternatives = append(alternatives, "("+strings.Join(comparison, " AND ")+")")
+		equals = append(equals, fmt.Sprintf("%s=:%s", b.keys[i], param))
+	}
+	b.where = append(b.where, "("+strings.Join(alternatives, " OR ")+")")
+
+	return b
+}
+
+// Limit bounds the number of rows, 0 for no limit
+func (b *SelectBuilder) Limit(limit int) *SelectBuilder {
+	b.limit = limit
+
+	return b
+}
+
+// Build returns the named statement and its parameters, as expected by the database handlers
+func (b *SelectBuilder) Build() (string, map[string]interface{}, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+
+	stmt := fmt.Sprintf("SELECT %s FROM %s", strings.Join(b.columns, ", "), b.table)
+	if len(b.where) > 0 {
+		stmt += " WHERE " + strings.Join(b.where, " AND ")
+	}
+	if len(b.keys) > 0 {
+		orders := make([]string, len(b.keys))
+		for i, key := range b.keys {
+			orders[i] = fmt.Sprintf("%s %s", key, b.sorts[i])
+		}
+		stmt += " ORDER BY " + strings.Join(orders, ", ")
+	}
+
+	params := map[string]interface{}{}
+	for key, value := range b.params {
+		params[key] = value
+	}
+	if b.limit > 0 {
+		stmt += " LIMIT :limit"
+		params["limit"] = b.limit
+	}
+
+	return stmt, params, nil
+}
+
+// SQL returns the statement with the positional placeholders of the bind type, like sqlx.DOLLAR, and its arguments
+func (b *SelectBuilder) SQL(bindType int) (string, []interface{}, error) {
+	stmt, params, err := b.Build()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return positional(bindType, stmt, params)
+}
+
+// check records an error when the column is not one of the model
+func (b *SelectBuilder) check(column string) bool {
+	if b.err != nil {
+		return false
+	}
+	if !b.known[column] {
+		b.err = fmt.Errorf("query: unknown column %q of %s", column, b.table)
+		return false
+	}
+
+	return true
+}
+
+// bind adds the value to the parameters and returns its name
+func (b *SelectBuilder) bind(value interface{}) string {
+	name := fmt.Sprintf("p%d", len(b.params))
+	b.params[name] = value
+
+	return name
+}
+
+// positional binds the named statement to the placeholders of the bind type
+func positional(bindType int, stmt string, arg interface{}) (string, []interface{}, error) {
+	stmt, args, err := sqlx.Named(stmt, arg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return sqlx.Rebind(bindType, stmt), args, nil
+}
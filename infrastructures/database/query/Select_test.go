@@ -0,0 +1,98 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type timestamps struct {
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type testModel struct {
+	ID       string
+	TenantID string `db:"tenant_id"`
+	Secret   string `db:"-"`
+	internal string
+	timestamps
+}
+
+func (m *testModel) GetModelName() string {
+	return "models"
+}
+
+func TestColumns(t *testing.T) {
+	columns := Columns(&testModel{})
+	if expected := []string{"id", "tenant_id", "created_at"}; !reflect.DeepEqual(columns, expected) {
+		t.Errorf("expected %v, got %v", expected, columns)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	stmt, params, err := Select(&testModel{}).
+		Where(Eq("tenant_id", "t1"), Gte("created_at", time.Unix(0, 0))).
+		OrderBy("created_at", Desc).
+		OrderBy("id", Asc).
+		After(time.Unix(10, 0), "a").
+		Limit(20).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "SELECT id, tenant_id, created_at FROM models WHERE tenant_id=:p0 AND created_at>=:p1 AND ((created_at<:p2) OR (created_at=:p2 AND id>:p3)) ORDER BY created_at DESC, id ASC LIMIT :limit"
+	if stmt != expected {
+		t.Errorf("expected %s, got %s", expected, stmt)
+	}
+	if len(params) != 5 || params["p0"] != "t1" || params["p3"] != "a" || params["limit"] != 20 {
+		t.Errorf("unexpected params %v", params)
+	}
+
+	// the placeholders follow the driver
+	stmt, args, err := Select(&testModel{}).Where(Eq("id", "a")).Limit(1).SQL(sqlx.DOLLAR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stmt != "SELECT id, tenant_id, created_at FROM models WHERE id=$1 LIMIT $2" || !reflect.DeepEqual(args, []interface{}{"a", 1}) {
+		t.Errorf("unexpected statement %s %v", stmt, args)
+	}
+}
+
+func TestSelectErrors(t *testing.T) {
+	builders := map[string]*SelectBuilder{
+		"unknown column":  Select(&testModel{}).Where(Eq("secret", "s")),
+		"unknown order":   Select(&testModel{}).OrderBy("id", Order("sideways")),
+		"cursor mismatch": Select(&testModel{}).OrderBy("id", Asc).After("a", "b"),
+	}
+
+	for name, builder := range builders {
+		if _, _, err := builder.Build(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestInsert(t *testing.T) {
+	stmt, err := Insert(&testModel{}).Omit("created_at").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stmt != "INSERT INTO models (id, tenant_id) VALUES (:id, :tenant_id)" {
+		t.Errorf("unexpected statement %s", stmt)
+	}
+
+	stmt, args, err := Insert(&testModel{ID: "a", TenantID: "t1"}).Omit("created_at").SQL(sqlx.QUESTION)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stmt != "INSERT INTO models (id, tenant_id) VALUES (?, ?)" || !reflect.DeepEqual(args, []interface{}{"a", "t1"}) {
+		t.Errorf("unexpected statement %s %v", stmt, args)
+	}
+
+	if _, err := Insert(&testModel{}).Omit("updated_at").Build(); err == nil {
+		t.Error("expected an error omitting an unknown column")
+	}
+}
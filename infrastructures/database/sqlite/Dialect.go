@@ -0,0 +1,40 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"gomora/infrastructures/database"
+)
+
+// Dialect classifies the errors of the sqlite driver
+type Dialect struct{}
+
+// IsConnectionError returns true when the statement failed to reach the database
+func (Dialect) IsConnectionError(err error) bool {
+	return database.IsConnectionError(err)
+}
+
+// IsRetriable returns true when the database stayed busy or locked past the busy timeout
+func (Dialect) IsRetriable(err error) bool {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code() & 0xff // primary result code
+		return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+	}
+
+	return database.IsConnectionError(err)
+}
+
+// TranslateError wraps the unique and primary key violations with database.ErrDuplicateEntry
+func (Dialect) TranslateError(err error) error {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) && (sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY) {
+		return fmt.Errorf("%w: %w", database.ErrDuplicateEntry, err)
+	}
+
+	return err
+}
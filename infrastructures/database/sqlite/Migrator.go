@@ -0,0 +1,34 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"gomora/infrastructures/database"
+	"gomora/infrastructures/database/sqlite/migrations"
+)
+
+// migrationDialect runs each migration as a whole
+// SQLite has no advisory lock, the write transactions of the migrations are serialized by the database itself.
+type migrationDialect struct{}
+
+// NewMigrator returns the migrator of the embedded sqlite migrations
+func NewMigrator(db *sqlx.DB) (*database.Migrator, error) {
+	return database.NewMigrator(db, migrationDialect{}, migrations.FS)
+}
+
+func (migrationDialect) Lock(ctx context.Context, conn *sql.Conn) error {
+	return nil
+}
+
+func (migrationDialect) Unlock(ctx context.Context, conn *sql.Conn) error {
+	return nil
+}
+
+// Statements keeps the migration whole, the driver runs the statements of a query one after the other
+// and the trigger bodies contain semicolons.
+func (migrationDialect) Statements(body string) []string {
+	return []string{body}
+}
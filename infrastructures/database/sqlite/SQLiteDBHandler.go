@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite" // pure go sqlite driver
+
+	"gomora/infrastructures/database"
+	"gomora/infrastructures/database/sqlite/types"
+)
+
+// MemoryPath opens a database that lives as long as the handler
+const MemoryPath = ":memory:"
+
+func init() {
+	// the modernc driver registers as "sqlite", unknown to sqlx
+	sqlx.BindDriver("sqlite", sqlx.QUESTION)
+}
+
+// SQLiteDBHandler handles sqlite operations
+// It is meant for local development and hermetic tests, not for production traffic.
+type SQLiteDBHandler struct {
+	database.SQLHandler
+}
+
+// Connect opens the sqlite database
+func (h *SQLiteDBHandler) Connect(params types.ConnectionParams) error {
+	if len(params.DBPath) == 0 {
+		params.DBPath = MemoryPath // default
+	}
+
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)", params.DBPath)
+	if params.DBPath != MemoryPath {
+		dsn += "&_pragma=journal_mode(WAL)"
+	}
+
+	conn, err := sqlx.Connect("sqlite", dsn)
+	if err != nil {
+		return err
+	}
+
+	// every connection to :memory: opens its own database, a single connection keeps them all on the same one
+	if params.DBPath == MemoryPath {
+		conn.SetMaxOpenConns(1)
+		conn.SetConnMaxLifetime(0)
+	} else {
+		conn.SetConnMaxLifetime(time.Minute * 4)
+	}
+
+	h.Conn = conn
+	h.RetryPolicy = params.Retry
+	h.Dialect = Dialect{}
+	h.SlowQueryThreshold = params.SlowQueryThreshold
+
+	fmt.Println("[SERVER] Database connected successfully")
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gomora/infrastructures/database"
+	"gomora/infrastructures/database/sqlite/types"
+)
+
+type record struct {
+	TenantID string `db:"tenant_id"`
+	ID       string `db:"id"`
+	OwnerID  string `db:"owner_id"`
+	Data     string `db:"data"`
+}
+
+func newHandler(t *testing.T) *SQLiteDBHandler {
+	h := &SQLiteDBHandler{}
+	if err := h.Connect(types.ConnectionParams{DBPath: MemoryPath}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { h.Close() })
+
+	migrator, err := NewMigrator(h.Conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.Up(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	return h
+}
+
+func TestMigrations(t *testing.T) {
+	h := newHandler(t)
+	ctx := context.Background()
+
+	migrator, _ := NewMigrator(h.Conn)
+	if err := migrator.Down(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.Up(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+	if version, dirty, err := migrator.Version(ctx); err != nil || dirty || version != 5 {
+		t.Errorf("expected the clean version 5, got %d (dirty: %t, err: %v)", version, dirty, err)
+	}
+}
+
+func TestStatements(t *testing.T) {
+	h := newHandler(t)
+	ctx := context.Background()
+	stmt := "INSERT INTO records (tenant_id, id, owner_id, data) VALUES (:tenant_id, :id, :owner_id, :data)"
+	inserted := record{TenantID: "default", ID: "1", OwnerID: "owner", Data: "data"}
+
+	if _, err := h.Execute(ctx, stmt, inserted); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.Execute(ctx, stmt, inserted); !errors.Is(err, database.ErrDuplicateEntry) {
+		t.Errorf("expected a duplicate entry, got %v", err)
+	}
+
+	var selected record
+	err := h.QueryRow(ctx, "SELECT tenant_id, id, owner_id, data FROM records WHERE tenant_id=:tenant_id AND id=:id", inserted, &selected)
+	if err != nil || selected != inserted {
+		t.Errorf("expected %+v, got %+v (%v)", inserted, selected, err)
+	}
+
+	// the unit of work is rolled back as a whole
+	err = h.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := h.Execute(ctx, stmt, record{TenantID: "default", ID: "2", Data: "data"}); err != nil {
+			return err
+		}
+
+		return errors.New("rollback")
+	})
+	if err == nil {
+		t.Fatal("expected the transaction to fail")
+	}
+
+	var records []record
+	if err := h.Query(ctx, "SELECT tenant_id, id, owner_id, data FROM records", map[string]interface{}{}, &records); err != nil || len(records) != 1 {
+		t.Errorf("expected a single record, got %d (%v)", len(records), err)
+	}
+}
+
+func TestAuditEventsAppendOnly(t *testing.T) {
+	h := newHandler(t)
+	ctx := context.Background()
+
+	_, err := h.Execute(ctx, "INSERT INTO audit_events (id, action, outcome) VALUES (:id, :action, :outcome)", map[string]interface{}{
+		"id":      "1",
+		"action":  "record.create",
+		"outcome": "success",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.Execute(ctx, "DELETE FROM audit_events WHERE id=:id", map[string]interface{}{"id": "1"}); err == nil {
+		t.Error("expected the delete to be rejected")
+	}
+}
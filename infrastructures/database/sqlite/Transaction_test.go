@@ -0,0 +1,220 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	dbTypes "gomora/infrastructures/database/types"
+)
+
+const insertRecord = "INSERT INTO records (tenant_id, id, owner_id, data) VALUES (:tenant_id, :id, :owner_id, :data)"
+
+// insert returns a unit of work inserting the records of the ids
+func insert(h *SQLiteDBHandler, ids ...string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		for _, id := range ids {
+			if _, err := h.Execute(ctx, insertRecord, record{TenantID: "default", ID: id, Data: "data"}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// recordIDs returns the ids of the saved records
+func recordIDs(t *testing.T, h *SQLiteDBHandler) string {
+	t.Helper()
+
+	var records []record
+	if err := h.Query(context.Background(), "SELECT tenant_id, id, owner_id, data FROM records ORDER BY id", map[string]interface{}{}, &records); err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, r := range records {
+		ids = append(ids, r.ID)
+	}
+
+	return fmt.Sprint(ids)
+}
+
+// withTransaction runs the unit of work and returns its panic as an error
+func withTransaction(h *SQLiteDBHandler, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+
+	return h.WithTransaction(context.Background(), fn)
+}
+
+func TestWithTransaction(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name     string
+		fn       func(h *SQLiteDBHandler) func(ctx context.Context) error
+		err      bool
+		expected string
+	}{
+		{
+			name:     "commits",
+			fn:       func(h *SQLiteDBHandler) func(ctx context.Context) error { return insert(h, "1", "2") },
+			expected: "[1 2]",
+		},
+		{
+			name: "rolls back on error",
+			fn: func(h *SQLiteDBHandler) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					if err := insert(h, "1")(ctx); err != nil {
+						return err
+					}
+
+					return errFailed
+				}
+			},
+			err:      true,
+			expected: "[]",
+		},
+		{
+			name: "rolls back on panic",
+			fn: func(h *SQLiteDBHandler) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					if err := insert(h, "1")(ctx); err != nil {
+						return err
+					}
+
+					panic(errFailed)
+				}
+			},
+			err:      true,
+			expected: "[]",
+		},
+		{
+			name: "commits nested savepoints",
+			fn: func(h *SQLiteDBHandler) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					if err := insert(h, "1")(ctx); err != nil {
+						return err
+					}
+
+					return h.WithTransaction(ctx, func(ctx context.Context) error {
+						if err := insert(h, "2")(ctx); err != nil {
+							return err
+						}
+
+						return h.WithTransaction(ctx, insert(h, "3"))
+					})
+				}
+			},
+			expected: "[1 2 3]",
+		},
+		{
+			name: "rolls back the failed savepoint only",
+			fn: func(h *SQLiteDBHandler) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					if err := insert(h, "1")(ctx); err != nil {
+						return err
+					}
+
+					err := h.WithTransaction(ctx, func(ctx context.Context) error {
+						if err := insert(h, "2")(ctx); err != nil {
+							return err
+						}
+
+						return errFailed
+					})
+					if err != errFailed {
+						return fmt.Errorf("expected the savepoint error, got %v", err)
+					}
+
+					return h.WithTransaction(ctx, insert(h, "3"))
+				}
+			},
+			expected: "[1 3]",
+		},
+		{
+			name: "rolls back everything on a nested panic",
+			fn: func(h *SQLiteDBHandler) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					if err := insert(h, "1")(ctx); err != nil {
+						return err
+					}
+
+					return h.WithTransaction(ctx, func(ctx context.Context) error {
+						if err := insert(h, "2")(ctx); err != nil {
+							return err
+						}
+
+						panic(errFailed)
+					})
+				}
+			},
+			err:      true,
+			expected: "[]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newHandler(t)
+
+			err := withTransaction(h, test.fn(h))
+			if (err != nil) != test.err {
+				t.Fatalf("expected error %t, got %v", test.err, err)
+			}
+
+			// the single connection of the memory database is released, or this would block
+			if ids := recordIDs(t, h); ids != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, ids)
+			}
+		})
+	}
+}
+
+func TestWithTransactionReplay(t *testing.T) {
+	h := newHandler(t)
+	h.RetryPolicy = dbTypes.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Microsecond}
+
+	// the unit of work is replayed as a whole, nested savepoints included
+	attempts := 0
+	err := h.WithTransaction(context.Background(), func(ctx context.Context) error {
+		attempts++
+
+		if err := insert(h, "1")(ctx); err != nil {
+			return err
+		}
+		if err := h.WithTransaction(ctx, insert(h, "2")); err != nil {
+			return err
+		}
+
+		if attempts == 1 {
+			return fmt.Errorf("insert: %w", driver.ErrBadConn)
+		}
+
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Fatalf("expected success after 2 attempts, got %v after %d", err, attempts)
+	}
+	if ids := recordIDs(t, h); ids != "[1 2]" {
+		t.Errorf("expected [1 2], got %s", ids)
+	}
+
+	// the errors that are not retriable are not replayed
+	attempts = 0
+	err = h.WithTransaction(context.Background(), func(ctx context.Context) error {
+		attempts++
+
+		return insert(h, "1")(ctx)
+	})
+	if err == nil || attempts != 1 {
+		t.Errorf("expected the duplicate entry after a single attempt, got %v after %d", err, attempts)
+	}
+}
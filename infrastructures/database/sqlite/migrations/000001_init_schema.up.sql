@@ -0,0 +1,7 @@
+CREATE TABLE
+    records (
+        id varchar(255) NOT NULL,
+        data varchar(255) NOT NULL,
+        created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (id)
+    );
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS records_owner_id_index;
+
+ALTER TABLE records DROP COLUMN owner_id;
@@ -0,0 +1,16 @@
+CREATE TABLE
+    records_owner (
+        id varchar(255) NOT NULL,
+        owner_id varchar(255) NOT NULL DEFAULT '',
+        data varchar(255) NOT NULL,
+        created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (id)
+    );
+
+INSERT INTO records_owner (id, owner_id, data, created_at) SELECT id, owner_id, data, created_at FROM records;
+
+DROP TABLE records;
+
+ALTER TABLE records_owner RENAME TO records;
+
+CREATE INDEX records_owner_id_index ON records (owner_id);
@@ -0,0 +1,18 @@
+-- sqlite can't alter the primary key, the table is rebuilt
+CREATE TABLE
+    records_tenant (
+        tenant_id varchar(64) NOT NULL DEFAULT 'default',
+        id varchar(255) NOT NULL,
+        owner_id varchar(255) NOT NULL DEFAULT '',
+        data varchar(255) NOT NULL,
+        created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (tenant_id, id)
+    );
+
+INSERT INTO records_tenant (id, owner_id, data, created_at) SELECT id, owner_id, data, created_at FROM records;
+
+DROP TABLE records;
+
+ALTER TABLE records_tenant RENAME TO records;
+
+CREATE INDEX records_tenant_id_owner_id_index ON records (tenant_id, owner_id);
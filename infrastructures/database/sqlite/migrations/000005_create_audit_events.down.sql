@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS audit_events_prevent_delete;
+
+DROP TRIGGER IF EXISTS audit_events_prevent_update;
+
+DROP TABLE IF EXISTS audit_events;
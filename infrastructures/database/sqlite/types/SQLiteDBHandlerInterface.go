@@ -0,0 +1,13 @@
+package types
+
+import (
+	"gomora/infrastructures/database/types"
+)
+
+// SQLiteDBHandlerInterface contains the implementable methods for the SQLite DB handler
+type SQLiteDBHandlerInterface interface {
+	types.DBHandlerInterface
+
+	// Connect opens the sqlite database
+	Connect(params ConnectionParams) error
+}
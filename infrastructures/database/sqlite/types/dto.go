@@ -0,0 +1,14 @@
+package types
+
+import (
+	"time"
+
+	"gomora/infrastructures/database/types"
+)
+
+type ConnectionParams struct {
+	DBPath string // path of the database file, or :memory:
+	Retry  types.RetryPolicy
+
+	SlowQueryThreshold time.Duration
+}
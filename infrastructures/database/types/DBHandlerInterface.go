@@ -0,0 +1,32 @@
+package types
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// TransactionHandlerInterface contains the implementable method to run a unit of work in a transaction
+type TransactionHandlerInterface interface {
+	// WithTransaction runs the unit of work in a transaction shared through the context
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// DBHandlerInterface contains the driver-neutral methods used by the repositories
+type DBHandlerInterface interface {
+	TransactionHandlerInterface
+
+	// Begin starts a new transaction
+	Begin() (*sqlx.Tx, error)
+	// Close closes the connections
+	Close() error
+	// Execute executes the statement following NamedExec
+	Execute(ctx context.Context, stmt string, model interface{}) (sql.Result, error)
+	// Query selects rows given by the sql statement
+	Query(ctx context.Context, qstmt string, model interface{}, bindModel interface{}) error
+	// QueryRow selects a row given by the sql statement
+	QueryRow(ctx context.Context, qstmt string, model interface{}, bindModel interface{}) error
+	// Stats returns the connection pool statistics
+	Stats() DBStats
+}
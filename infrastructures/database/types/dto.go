@@ -0,0 +1,29 @@
+package types
+
+import (
+	"database/sql"
+	"time"
+)
+
+// RetryPolicy holds the retry settings of deadlocks and transient errors
+// Zero values fall back to 3 attempts with a backoff from 50ms up to 1s.
+type RetryPolicy struct {
+	MaxAttempts int // 1 disables retries
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// PoolParams holds the connection pool settings
+// Zero values keep the database/sql defaults, except ConnMaxLifetime which defaults to 4m.
+type PoolParams struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// DBStats holds the connection pool statistics of the primary and the replicas
+type DBStats struct {
+	Primary  sql.DBStats   `json:"primary"`
+	Replicas []sql.DBStats `json:"replicas"`
+}
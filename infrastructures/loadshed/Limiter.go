@@ -0,0 +1,137 @@
+package loadshed
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gomora/infrastructures/loadshed/types"
+)
+
+// Priority orders the requests to shed, the writes first
+type Priority int
+
+// the priorities of the requests
+const (
+	PriorityWrite    Priority = iota // admitted up to writeShare of the limit
+	PriorityRead                     // admitted up to the limit
+	PriorityCritical                 // always admitted, like the health checks
+)
+
+// writeShare is the share of the limit the writes can take, the rest is kept for the reads
+const writeShare = 0.75
+
+// Limiter adapts the number of concurrent requests to the observed latency (AIMD)
+// The limit grows by one for every request served in time while it is in use, and is cut by the backoff
+// ratio when a request is slow or fails, at most once per generation of requests: the requests started
+// before a cut don't cut it again.
+type Limiter struct {
+	params types.LimiterParams
+
+	mu          sync.Mutex
+	limit       float64
+	inFlight    int
+	lastCutAt   time.Time
+	rejected    atomic.Int64
+	nowFunction func() time.Time
+}
+
+// Stats holds the state of the limiter
+type Stats struct {
+	Limit    int
+	InFlight int
+	Rejected int64
+}
+
+// NewLimiter returns a limiter starting at the initial limit
+func NewLimiter(params types.LimiterParams) *Limiter {
+	if params.MinLimit <= 0 {
+		params.MinLimit = 10
+	}
+	if params.MaxLimit <= 0 {
+		params.MaxLimit = 1000
+	}
+	if params.InitialLimit <= 0 {
+		params.InitialLimit = 100
+	}
+	params.MaxLimit = max(params.MaxLimit, params.MinLimit)
+	params.InitialLimit = min(max(params.InitialLimit, params.MinLimit), params.MaxLimit)
+	if params.Latency <= 0 {
+		params.Latency = time.Second
+	}
+	if params.Backoff <= 0 || params.Backoff >= 1 {
+		params.Backoff = 0.9
+	}
+
+	return &Limiter{
+		params:      params,
+		limit:       float64(params.InitialLimit),
+		nowFunction: time.Now,
+	}
+}
+
+// Acquire admits the request, false when it must be shed
+// The release reports the outcome of the admitted request, failed when the server could not serve it.
+func (l *Limiter) Acquire(priority Priority) (release func(failed bool), ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	capacity := l.limit
+	switch priority {
+	case PriorityCritical:
+		capacity = math.Inf(1)
+	case PriorityWrite:
+		capacity = l.limit * writeShare
+	}
+
+	if float64(l.inFlight) >= capacity {
+		l.rejected.Add(1)
+		return nil, false
+	}
+
+	l.inFlight++
+	startedAt := l.nowFunction()
+
+	var once sync.Once
+	return func(failed bool) {
+		once.Do(func() {
+			l.release(startedAt, failed)
+		})
+	}, true
+}
+
+func (l *Limiter) release(startedAt time.Time, failed bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.nowFunction()
+	inFlight := l.inFlight
+	l.inFlight--
+
+	if failed || now.Sub(startedAt) > l.params.Latency {
+		if startedAt.After(l.lastCutAt) {
+			l.limit = max(l.limit*l.params.Backoff, float64(l.params.MinLimit))
+			l.lastCutAt = now
+		}
+
+		return
+	}
+
+	// the limit only grows while it is the bottleneck
+	if float64(inFlight)*2 >= l.limit {
+		l.limit = min(l.limit+1, float64(l.params.MaxLimit))
+	}
+}
+
+// Stats returns the current limit, the requests in flight and the number of requests shed
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return Stats{
+		Limit:    int(l.limit),
+		InFlight: l.inFlight,
+		Rejected: l.rejected.Load(),
+	}
+}
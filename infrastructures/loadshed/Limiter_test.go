@@ -0,0 +1,91 @@
+package loadshed
+
+import (
+	"testing"
+	"time"
+
+	"gomora/infrastructures/loadshed/types"
+)
+
+func TestLimiterPriorities(t *testing.T) {
+	limiter := NewLimiter(types.LimiterParams{InitialLimit: 4, MinLimit: 4, MaxLimit: 4})
+
+	// the writes take up to 3 slots of 4, the reads the last one
+	for i := 0; i < 3; i++ {
+		if _, ok := limiter.Acquire(PriorityWrite); !ok {
+			t.Fatalf("expected write %d to be admitted", i)
+		}
+	}
+	if _, ok := limiter.Acquire(PriorityWrite); ok {
+		t.Error("expected the fourth write to be shed")
+	}
+	if _, ok := limiter.Acquire(PriorityRead); !ok {
+		t.Error("expected a read to be admitted")
+	}
+	if _, ok := limiter.Acquire(PriorityRead); ok {
+		t.Error("expected the second read to be shed")
+	}
+
+	// the health checks are never shed
+	release, ok := limiter.Acquire(PriorityCritical)
+	if !ok {
+		t.Fatal("expected a health check to be admitted")
+	}
+	release(false)
+
+	if stats := limiter.Stats(); stats.InFlight != 4 || stats.Rejected != 2 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestLimiterAIMD(t *testing.T) {
+	limiter := NewLimiter(types.LimiterParams{InitialLimit: 10, MinLimit: 5, MaxLimit: 12, Latency: time.Second, Backoff: 0.5})
+	now := time.Now()
+	limiter.nowFunction = func() time.Time { return now }
+
+	// the limit grows while it is in use, up to the max
+	for i := 0; i < 5; i++ {
+		var releases []func(bool)
+		for j := 0; j < 8; j++ {
+			release, _ := limiter.Acquire(PriorityRead)
+			releases = append(releases, release)
+		}
+		for _, release := range releases {
+			release(false)
+		}
+	}
+	if limit := limiter.Stats().Limit; limit != 12 {
+		t.Fatalf("expected the limit to grow to 12, got %d", limit)
+	}
+
+	// but not while idle
+	release, _ := limiter.Acquire(PriorityRead)
+	release(false)
+	if limit := limiter.Stats().Limit; limit != 12 {
+		t.Fatalf("expected the limit to stay at 12, got %d", limit)
+	}
+
+	// the slow requests of a generation cut it once
+	var releases []func(bool)
+	for i := 0; i < 4; i++ {
+		release, _ := limiter.Acquire(PriorityRead)
+		releases = append(releases, release)
+	}
+	now = now.Add(time.Second * 2)
+	for _, release := range releases {
+		release(false)
+	}
+	if limit := limiter.Stats().Limit; limit != 6 {
+		t.Fatalf("expected the limit to be cut to 6, got %d", limit)
+	}
+
+	// then down to the min, on failures
+	for i := 0; i < 3; i++ {
+		now = now.Add(time.Millisecond)
+		release, _ := limiter.Acquire(PriorityRead)
+		release(true)
+	}
+	if limit := limiter.Stats().Limit; limit != 5 {
+		t.Fatalf("expected the limit to be cut to 5, got %d", limit)
+	}
+}
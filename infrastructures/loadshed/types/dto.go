@@ -0,0 +1,14 @@
+package types
+
+import (
+	"time"
+)
+
+// LimiterParams holds the settings of the concurrency limiter
+type LimiterParams struct {
+	InitialLimit int           // defaults to 100
+	MinLimit     int           // defaults to 10
+	MaxLimit     int           // defaults to 1000
+	Latency      time.Duration // slower requests signal an overload, defaults to 1s
+	Backoff      float64       // ratio the limit is cut by on overload, defaults to 0.9
+}
@@ -0,0 +1,76 @@
+package maintenance
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// WatchFile switches the maintenance mode from the file at the path until the context is done
+// The file holds the mode on its first line, full when empty, then the optional reason. Creating or
+// changing it switches the mode, removing it turns the maintenance off. The switches made otherwise,
+// like from the admin endpoint, are kept until the file changes.
+func WatchFile(ctx context.Context, path string, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Second * 5
+	}
+
+	var last []byte
+	exists := false
+	apply := func() {
+		content, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			if exists {
+				exists, last = false, nil
+				_, _ = Set(ModeOff, "")
+				log.Printf("[MAINTENANCE] %s removed, maintenance off", path)
+			}
+			return
+		}
+		if err != nil {
+			log.Printf("[MAINTENANCE] failed to read %s: %v", path, err)
+			return
+		}
+
+		if exists && bytes.Equal(content, last) {
+			return
+		}
+		exists, last = true, content
+
+		mode, reason := parseFile(content)
+		if _, err := Set(mode, reason); err != nil {
+			log.Printf("[MAINTENANCE] invalid mode %q in %s", mode, path)
+			return
+		}
+		log.Printf("[MAINTENANCE] %s changed, maintenance %s", path, mode)
+	}
+
+	apply()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			apply()
+		}
+	}
+}
+
+// parseFile returns the mode and the reason of the maintenance file
+func parseFile(content []byte) (string, string) {
+	mode, reason, _ := strings.Cut(string(content), "\n")
+	mode = strings.TrimSpace(mode)
+	if len(mode) == 0 {
+		mode = ModeFull
+	}
+
+	return mode, strings.TrimSpace(reason)
+}
@@ -0,0 +1,59 @@
+package maintenance
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// the maintenance modes
+const (
+	ModeOff      = "off"
+	ModeReadOnly = "read-only" // the writes are rejected, the queries keep working
+	ModeFull     = "full"      // every api call is rejected
+)
+
+// ErrInvalidMode is returned for the unknown modes
+var ErrInvalidMode = errors.New("invalid maintenance mode")
+
+// Status holds the maintenance mode of the server
+type Status struct {
+	Mode   string
+	Reason string
+	Since  time.Time
+}
+
+var current atomic.Pointer[Status]
+
+func init() {
+	current.Store(&Status{Mode: ModeOff, Since: time.Now()})
+}
+
+// Current returns the maintenance status
+func Current() Status {
+	return *current.Load()
+}
+
+// Set switches the maintenance mode, the reason is shown to the operators
+func Set(mode, reason string) (Status, error) {
+	switch mode {
+	case ModeOff, ModeReadOnly, ModeFull:
+	default:
+		return Status{}, ErrInvalidMode
+	}
+
+	status := &Status{Mode: mode, Reason: reason, Since: time.Now()}
+	current.Store(status)
+
+	return *status, nil
+}
+
+// BlocksWrites returns true when the writes are rejected
+func (status Status) BlocksWrites() bool {
+	return status.Mode != ModeOff
+}
+
+// BlocksReads returns true when the queries are rejected as well
+func (status Status) BlocksReads() bool {
+	return status.Mode == ModeFull
+}
@@ -0,0 +1,64 @@
+package maintenance
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSet(t *testing.T) {
+	defer Set(ModeOff, "")
+
+	if _, err := Set("partial", ""); err != ErrInvalidMode {
+		t.Errorf("expected %v, got %v", ErrInvalidMode, err)
+	}
+
+	status, err := Set(ModeReadOnly, "failover")
+	if err != nil || Current() != status || !status.BlocksWrites() || status.BlocksReads() {
+		t.Errorf("unexpected status %+v, %v", status, err)
+	}
+}
+
+func TestWatchFile(t *testing.T) {
+	defer Set(ModeOff, "")
+
+	path := filepath.Join(t.TempDir(), "maintenance")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go WatchFile(ctx, path, time.Millisecond*10)
+
+	eventually := func(mode, reason string) {
+		t.Helper()
+
+		deadline := time.Now().Add(time.Second)
+		for status := Current(); status.Mode != mode || status.Reason != reason; status = Current() {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %s (%s), got %+v", mode, reason, status)
+			}
+			time.Sleep(time.Millisecond * 5)
+		}
+	}
+
+	// an empty file is a full maintenance
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	eventually(ModeFull, "")
+
+	if err := os.WriteFile(path, []byte("read-only\nschema migration\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	eventually(ModeReadOnly, "schema migration")
+
+	// the switches made otherwise are kept while the file is unchanged
+	_, _ = Set(ModeFull, "admin")
+	time.Sleep(time.Millisecond * 50)
+	eventually(ModeFull, "admin")
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	eventually(ModeOff, "")
+}
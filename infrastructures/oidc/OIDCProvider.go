@@ -0,0 +1,280 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+
+	"gomora/infrastructures/oidc/types"
+	"gomora/internal/auth"
+)
+
+const (
+	defaultJWKSCacheTTL = time.Minute * 15
+	// minimum interval between two key set fetches triggered by unknown key ids
+	minJWKSRefreshInterval = time.Minute
+	acceptableSkew         = time.Minute
+)
+
+var (
+	// ErrInvalidToken is returned when the token cannot be verified against the provider
+	ErrInvalidToken = errors.New("invalid oidc token")
+)
+
+// OIDCProvider verifies the tokens issued by an external OpenID Connect provider
+type OIDCProvider struct {
+	params types.ProviderParams
+
+	mu          sync.Mutex
+	keySet      jwk.Set
+	fetchedAt   time.Time
+	refreshedAt time.Time
+
+	// a single fetch of the provider documents at a time, the cache is not locked meanwhile
+	fetchMu sync.Mutex
+	jwksURI string
+}
+
+type discoveryDocument struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+// NewOIDCProvider creates the provider, the discovery document and the key set are fetched on first use
+func NewOIDCProvider(params types.ProviderParams) *OIDCProvider {
+	if len(params.SubjectClaim) == 0 {
+		params.SubjectClaim = "sub"
+	}
+	if params.JWKSCacheTTL <= 0 {
+		params.JWKSCacheTTL = defaultJWKSCacheTTL
+	}
+	if params.HTTPClient == nil {
+		params.HTTPClient = &http.Client{Timeout: time.Second * 10}
+	}
+
+	return &OIDCProvider{params: params}
+}
+
+// ParseScopeMapping parses the "providerValue=scope scope;providerValue=scope" mapping
+func ParseScopeMapping(mapping string) map[string][]string {
+	scopeMapping := map[string][]string{}
+
+	for _, entry := range strings.Split(mapping, ";") {
+		value, scopes, ok := strings.Cut(entry, "=")
+		if !ok || len(strings.TrimSpace(value)) == 0 {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		scopeMapping[value] = append(scopeMapping[value], strings.Fields(scopes)...)
+	}
+
+	return scopeMapping
+}
+
+// IssuedBy returns true when the unverified token claims to be issued by the issuer
+// It only tells which verifier the token is meant for, the token must be verified afterwards.
+func IssuedBy(token string, issuer string) bool {
+	if len(token) == 0 || len(issuer) == 0 {
+		return false
+	}
+
+	unverified, err := jwt.ParseInsecure([]byte(token))
+	if err != nil {
+		return false
+	}
+
+	return unverified.Issuer() == issuer
+}
+
+// Issuer returns the issuer the provider accepts tokens from
+func (p *OIDCProvider) Issuer() string {
+	return p.params.Issuer
+}
+
+// Verify validates the token issued by the provider and maps its claims into the caller identity
+func (p *OIDCProvider) Verify(ctx context.Context, token string) (auth.Identity, error) {
+	msg, err := jws.ParseString(token)
+	if err != nil || len(msg.Signatures()) == 0 {
+		return auth.Identity{}, ErrInvalidToken
+	}
+
+	keySet, err := p.getKeySet(ctx, msg.Signatures()[0].ProtectedHeaders().KeyID())
+	if err != nil {
+		return auth.Identity{}, err
+	}
+
+	// the tokens issued to the other clients of the provider are not ours
+	if len(p.params.Audience) == 0 {
+		return auth.Identity{}, ErrInvalidToken
+	}
+
+	verified, err := jwt.ParseString(token,
+		jwt.WithKeySet(keySet, jws.WithInferAlgorithmFromKey(true)),
+		jwt.WithValidate(true),
+		jwt.WithIssuer(p.params.Issuer),
+		jwt.WithAudience(p.params.Audience),
+		jwt.WithAcceptableSkew(acceptableSkew),
+	)
+	if err != nil {
+		return auth.Identity{}, ErrInvalidToken
+	}
+
+	claims, err := verified.AsMap(ctx)
+	if err != nil {
+		return auth.Identity{}, ErrInvalidToken
+	}
+
+	return p.mapClaims(claims)
+}
+
+// mapClaims maps the provider claims into the caller identity
+func (p *OIDCProvider) mapClaims(claims map[string]interface{}) (auth.Identity, error) {
+	identity := auth.Identity{}
+
+	subject, _ := claims[p.params.SubjectClaim].(string)
+	if len(subject) == 0 {
+		return auth.Identity{}, ErrInvalidToken
+	}
+	identity.Subject = subject
+
+	if len(p.params.TenantClaim) > 0 {
+		identity.TenantID, _ = claims[p.params.TenantClaim].(string)
+	}
+
+	if len(p.params.ScopeClaim) == 0 {
+		return identity, nil
+	}
+
+	var values []string
+	switch claim := claims[p.params.ScopeClaim].(type) {
+	case string:
+		values = strings.Fields(claim)
+	case []string:
+		values = claim
+	case []interface{}:
+		for _, v := range claim {
+			if str, ok := v.(string); ok {
+				values = append(values, str)
+			}
+		}
+	}
+
+	// the unmapped values grant nothing, a provider group named like one of our scopes included
+	for _, value := range values {
+		identity.Scopes = append(identity.Scopes, p.params.ScopeMapping[value]...)
+	}
+
+	return identity, nil
+}
+
+// getKeySet returns the cached key set of the provider
+// The key set is fetched again when expired or when it does not hold the key id, at most once per minute for the latter.
+// The verifications don't wait for a fetch in progress when a previous key set can be served.
+func (p *OIDCProvider) getKeySet(ctx context.Context, keyID string) (jwk.Set, error) {
+	keySet, fresh := p.cachedKeySet(keyID)
+	if fresh {
+		return keySet, nil
+	}
+
+	if !p.fetchMu.TryLock() {
+		if keySet != nil {
+			return keySet, nil
+		}
+
+		p.fetchMu.Lock()
+	}
+	defer p.fetchMu.Unlock()
+
+	// fetched by another caller meanwhile
+	if keySet, fresh = p.cachedKeySet(keyID); fresh {
+		return keySet, nil
+	}
+
+	if len(p.jwksURI) == 0 {
+		jwksURI, err := p.discover(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		p.jwksURI = jwksURI
+	}
+
+	fetched, err := jwk.Fetch(ctx, p.jwksURI, jwk.WithHTTPClient(p.params.HTTPClient))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.refreshedAt = time.Now()
+	if err != nil {
+		// keep serving the previous key set while the provider is unreachable
+		if p.keySet != nil {
+			return p.keySet, nil
+		}
+
+		return nil, fmt.Errorf("[OIDC] fetching key set failed: %w", err)
+	}
+
+	p.keySet = fetched
+	p.fetchedAt = p.refreshedAt
+
+	return fetched, nil
+}
+
+// cachedKeySet returns the cached key set, fresh when it can be served without fetching it again
+func (p *OIDCProvider) cachedKeySet(keyID string) (jwk.Set, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if p.keySet == nil || now.Sub(p.fetchedAt) > p.params.JWKSCacheTTL {
+		return p.keySet, false
+	}
+
+	if _, ok := p.keySet.LookupKeyID(keyID); ok || now.Sub(p.refreshedAt) < minJWKSRefreshInterval {
+		return p.keySet, true
+	}
+
+	return p.keySet, false
+}
+
+// discover fetches the discovery document of the provider and returns its key set uri
+func (p *OIDCProvider) discover(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.params.Issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := p.params.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("[OIDC] fetching discovery document failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("[OIDC] fetching discovery document failed: %s", res.Status)
+	}
+
+	var document discoveryDocument
+	if err := json.NewDecoder(res.Body).Decode(&document); err != nil {
+		return "", fmt.Errorf("[OIDC] decoding discovery document failed: %w", err)
+	}
+
+	if document.Issuer != p.params.Issuer {
+		return "", fmt.Errorf("[OIDC] discovery document issuer %q does not match %q", document.Issuer, p.params.Issuer)
+	}
+	if len(document.JWKSURI) == 0 {
+		return "", errors.New("[OIDC] discovery document has no jwks_uri")
+	}
+
+	return document.JWKSURI, nil
+}
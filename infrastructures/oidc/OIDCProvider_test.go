@@ -0,0 +1,240 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+
+	"gomora/infrastructures/oidc/types"
+)
+
+// testIdP is a local stand-in for an OpenID Connect provider
+type testIdP struct {
+	server      *httptest.Server
+	key         jwk.Key
+	keySet      jwk.Set
+	jwksFetches int32
+}
+
+func newTestIdP(t *testing.T) *testIdP {
+	t.Helper()
+
+	idp := &testIdP{keySet: jwk.NewSet()}
+	idp.rotateKey(t, "key-1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   idp.server.URL,
+			"jwks_uri": idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&idp.jwksFetches, 1)
+		_ = json.NewEncoder(w).Encode(idp.keySet)
+	})
+
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+// rotateKey replaces the signing key, the public key set only holds the new key
+func (idp *testIdP) rotateKey(t *testing.T, keyID string) {
+	t.Helper()
+
+	raw, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := jwk.FromRaw(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = key.Set(jwk.KeyIDKey, keyID)
+	_ = key.Set(jwk.AlgorithmKey, jwa.RS256)
+
+	public, err := key.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idp.key = key
+	idp.keySet = jwk.NewSet()
+	_ = idp.keySet.AddKey(public)
+}
+
+func (idp *testIdP) issue(t *testing.T, claims map[string]interface{}) string {
+	t.Helper()
+
+	token := jwt.New()
+	_ = token.Set(jwt.IssuerKey, idp.server.URL)
+	_ = token.Set(jwt.AudienceKey, "gomora")
+	_ = token.Set(jwt.SubjectKey, "user-1")
+	_ = token.Set(jwt.IssuedAtKey, time.Now())
+	_ = token.Set(jwt.ExpirationKey, time.Now().Add(time.Minute*5))
+	for k, v := range claims {
+		_ = token.Set(k, v)
+	}
+
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.RS256, idp.key))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(signed)
+}
+
+func TestVerify(t *testing.T) {
+	idp := newTestIdP(t)
+	provider := NewOIDCProvider(types.ProviderParams{
+		Issuer:       idp.server.URL,
+		Audience:     "gomora",
+		ScopeClaim:   "groups",
+		ScopeMapping: ParseScopeMapping("gomora-admins=admin;everyone=records:read"),
+		TenantClaim:  "org",
+	})
+
+	identity, err := provider.Verify(context.Background(), idp.issue(t, map[string]interface{}{
+		"groups": []string{"gomora-admins", "unmapped"},
+		"org":    "acme",
+	}))
+	if err != nil {
+		t.Fatalf("valid token rejected: %v", err)
+	}
+
+	if identity.Subject != "user-1" || identity.TenantID != "acme" || !identity.IsAdmin() || len(identity.Scopes) != 1 {
+		t.Errorf("unexpected identity %+v", identity)
+	}
+
+	// the key set is cached between verifications
+	if _, err := provider.Verify(context.Background(), idp.issue(t, nil)); err != nil {
+		t.Fatalf("valid token rejected: %v", err)
+	}
+	if fetches := atomic.LoadInt32(&idp.jwksFetches); fetches != 1 {
+		t.Errorf("expected 1 key set fetch, got %d", fetches)
+	}
+}
+
+func TestVerifyRejectsInvalidTokens(t *testing.T) {
+	idp := newTestIdP(t)
+	provider := NewOIDCProvider(types.ProviderParams{
+		Issuer:   idp.server.URL,
+		Audience: "gomora",
+	})
+
+	cases := map[string]map[string]interface{}{
+		"wrong audience": {jwt.AudienceKey: "someone-else"},
+		"wrong issuer":   {jwt.IssuerKey: "https://evil.example.com"},
+		"expired":        {jwt.ExpirationKey: time.Now().Add(-time.Hour)},
+		"no subject":     {jwt.SubjectKey: ""},
+	}
+
+	for name, claims := range cases {
+		if _, err := provider.Verify(context.Background(), idp.issue(t, claims)); err == nil {
+			t.Errorf("%s: token accepted", name)
+		}
+	}
+
+	if _, err := provider.Verify(context.Background(), "not-a-token"); err == nil {
+		t.Error("malformed token accepted")
+	}
+}
+
+func TestVerifyRequiresAudience(t *testing.T) {
+	idp := newTestIdP(t)
+	provider := NewOIDCProvider(types.ProviderParams{
+		Issuer: idp.server.URL,
+	})
+
+	if _, err := provider.Verify(context.Background(), idp.issue(t, nil)); err != ErrInvalidToken {
+		t.Errorf("expected %v, got %v", ErrInvalidToken, err)
+	}
+}
+
+func TestVerifyUnmappedScopes(t *testing.T) {
+	idp := newTestIdP(t)
+	provider := NewOIDCProvider(types.ProviderParams{
+		Issuer:     idp.server.URL,
+		Audience:   "gomora",
+		ScopeClaim: "groups",
+	})
+
+	// a provider group named like one of our scopes grants nothing
+	identity, err := provider.Verify(context.Background(), idp.issue(t, map[string]interface{}{
+		"groups": []string{"admin"},
+	}))
+	if err != nil || len(identity.Scopes) != 0 {
+		t.Errorf("expected no scopes, got %+v, %v", identity, err)
+	}
+}
+
+func TestVerifyDuringKeySetFetch(t *testing.T) {
+	idp := newTestIdP(t)
+	provider := NewOIDCProvider(types.ProviderParams{
+		Issuer:   idp.server.URL,
+		Audience: "gomora",
+	})
+
+	token := idp.issue(t, nil)
+	if _, err := provider.Verify(context.Background(), token); err != nil {
+		t.Fatalf("valid token rejected: %v", err)
+	}
+
+	// the previous key set is served while another caller is fetching it
+	provider.fetchMu.Lock()
+	defer provider.fetchMu.Unlock()
+	provider.fetchedAt = time.Now().Add(-time.Hour)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := provider.Verify(context.Background(), token)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("valid token rejected: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("the verification waited for the fetch")
+	}
+}
+
+func TestVerifyAfterKeyRotation(t *testing.T) {
+	idp := newTestIdP(t)
+	provider := NewOIDCProvider(types.ProviderParams{
+		Issuer:   idp.server.URL,
+		Audience: "gomora",
+	})
+
+	if _, err := provider.Verify(context.Background(), idp.issue(t, nil)); err != nil {
+		t.Fatalf("valid token rejected: %v", err)
+	}
+
+	idp.rotateKey(t, "key-2")
+	token := idp.issue(t, nil)
+
+	// unknown key ids do not refetch the key set more than once per minute
+	if _, err := provider.Verify(context.Background(), token); err == nil {
+		t.Fatal("token signed with the unknown key accepted before refresh")
+	}
+
+	provider.refreshedAt = time.Now().Add(-minJWKSRefreshInterval)
+	if _, err := provider.Verify(context.Background(), token); err != nil {
+		t.Fatalf("token signed with the rotated key rejected: %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+package types
+
+import (
+	"context"
+
+	"gomora/internal/auth"
+)
+
+// OIDCProviderInterface contains the implementable methods for the OpenID Connect provider
+type OIDCProviderInterface interface {
+	// Issuer returns the issuer the provider accepts tokens from
+	Issuer() string
+	// Verify validates the token issued by the provider and maps its claims into the caller identity
+	Verify(ctx context.Context, token string) (auth.Identity, error)
+}
@@ -0,0 +1,18 @@
+package types
+
+import (
+	"net/http"
+	"time"
+)
+
+// ProviderParams holds the settings of the OIDC provider verifying the tokens of the Issuer
+type ProviderParams struct {
+	Issuer       string
+	Audience     string              // expected aud claim, required
+	SubjectClaim string              // defaults to sub
+	ScopeClaim   string              // claim holding the provider groups or roles, skipped when empty
+	ScopeMapping map[string][]string // provider values to internal scopes, the unmapped values grant none
+	TenantClaim  string              // claim binding the identity to a tenant, skipped when empty
+	JWKSCacheTTL time.Duration       // defaults to 15 minutes
+	HTTPClient   *http.Client
+}
@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"time"
+
+	"gomora/infrastructures/ratelimit/types"
+)
+
+// interval returns the time to refill a token of the limit
+// It is at least a microsecond, so that the rates over types.MaxRate or not positive don't divide by zero.
+func interval(limit types.Limit) time.Duration {
+	if limit.Rate <= 0 || limit.Rate > types.MaxRate {
+		return time.Microsecond
+	}
+
+	return time.Duration(float64(time.Second) / limit.Rate)
+}
+
+// take applies the generic cell rate algorithm, a token bucket keeping only the theoretical arrival time (tat)
+// of the next request: the bucket is full when tat is past, and empty when tat is a whole burst ahead.
+// It returns the new tat, unchanged when the request is rejected.
+func take(tat, now time.Time, limit types.Limit) (time.Time, types.Result) {
+	interval := interval(limit)
+	if tat.Before(now) {
+		tat = now
+	}
+
+	next := tat.Add(interval)
+	allowAt := next.Add(-interval * time.Duration(limit.Burst))
+	if now.Before(allowAt) {
+		return tat, types.Result{
+			Limit:      limit.Burst,
+			Reset:      tat.Sub(now),
+			RetryAfter: allowAt.Sub(now),
+		}
+	}
+
+	return next, types.Result{
+		Allowed:   true,
+		Limit:     limit.Burst,
+		Remaining: int(now.Sub(allowAt) / interval),
+		Reset:     next.Sub(now),
+	}
+}
@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"gomora/infrastructures/ratelimit/types"
+	"gomora/internal/auth"
+	"gomora/internal/requestinfo"
+)
+
+// storeErrorLogInterval spaces the logs of a failing shared store, which fails on every request
+const storeErrorLogInterval = time.Minute
+
+// Limiter applies the limits of the routes to every client
+// The buckets are kept in the shared store when set, or in memory. When the shared store fails, the limits
+// are applied per instance until it recovers.
+type Limiter struct {
+	limits map[string]types.Limit
+	store  types.RateLimitStoreInterface
+	local  *MemoryStore
+
+	lastStoreErrorLog atomic.Int64
+}
+
+// NewLimiter returns a limiter of the routes, the store is optional
+func NewLimiter(limits map[string]types.Limit, store types.RateLimitStoreInterface) *Limiter {
+	return &Limiter{
+		limits: limits,
+		store:  store,
+		local:  NewMemoryStore(),
+	}
+}
+
+// Take takes a token of the client on the route, false when the route is not limited
+func (l *Limiter) Take(ctx context.Context, route, client string) (types.Result, bool) {
+	limit, ok := l.limits[route]
+	if !ok {
+		return types.Result{}, false
+	}
+
+	key := route + ":" + client
+	if l.store != nil {
+		result, err := l.store.Take(ctx, key, limit)
+		if err == nil {
+			return result, true
+		}
+
+		if now := time.Now().UnixNano(); now-l.lastStoreErrorLog.Load() > int64(storeErrorLogInterval) {
+			l.lastStoreErrorLog.Store(now)
+			log.Printf("[RATELIMIT] shared store failed, limiting per instance: %v", err)
+		}
+	}
+
+	result, _ := l.local.Take(ctx, key, limit)
+
+	return result, true
+}
+
+// ClientKey identifies the caller of the request: the api key, the subject of the token, or the ip
+func ClientKey(ctx context.Context) string {
+	if identity, ok := auth.FromContext(ctx); ok {
+		if len(identity.APIKeyID) > 0 {
+			return "apikey:" + identity.APIKeyID
+		}
+
+		return "sub:" + identity.TenantID + ":" + identity.Subject
+	}
+
+	return IPKey(ctx)
+}
+
+// IPKey returns the key of the client ip of the context
+// The ip is the peer address, or the address forwarded by a trusted proxy, so the callers can't choose it.
+func IPKey(ctx context.Context) string {
+	return "ip:" + requestinfo.FromContext(ctx).IP
+}
+
+// Headers returns the quota headers of the result, with Retry-After when rejected
+func Headers(result types.Result) map[string]string {
+	headers := map[string]string{
+		"RateLimit-Limit":     strconv.Itoa(result.Limit),
+		"RateLimit-Remaining": strconv.Itoa(result.Remaining),
+		"RateLimit-Reset":     strconv.Itoa(seconds(result.Reset)),
+	}
+	if !result.Allowed {
+		headers["Retry-After"] = strconv.Itoa(max(seconds(result.RetryAfter), 1))
+	}
+
+	return headers
+}
+
+// seconds rounds the duration up to whole seconds
+func seconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,179 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"gomora/infrastructures/ratelimit/types"
+	"gomora/infrastructures/redis"
+	"gomora/infrastructures/redis/redistest"
+	redisTypes "gomora/infrastructures/redis/types"
+	"gomora/internal/auth"
+	"gomora/internal/requestinfo"
+)
+
+func TestTake(t *testing.T) {
+	limit := types.Limit{Rate: 10, Burst: 3}
+	now := time.Now()
+
+	// the burst is served right away, then the bucket is empty
+	var tat time.Time
+	var result types.Result
+	for i := 2; i >= 0; i-- {
+		tat, result = take(tat, now, limit)
+		if !result.Allowed || result.Remaining != i {
+			t.Fatalf("expected %d remaining, got %+v", i, result)
+		}
+	}
+
+	tat, result = take(tat, now, limit)
+	if result.Allowed || result.RetryAfter != time.Millisecond*100 || result.Reset != time.Millisecond*300 {
+		t.Fatalf("expected a rejection for 100ms, got %+v", result)
+	}
+
+	// a token is refilled every 100ms
+	if _, result = take(tat, now.Add(time.Millisecond*100), limit); !result.Allowed || result.Remaining != 0 {
+		t.Errorf("expected a refilled token, got %+v", result)
+	}
+	if _, result = take(tat, now.Add(time.Second), limit); !result.Allowed || result.Remaining != 2 {
+		t.Errorf("expected a full bucket, got %+v", result)
+	}
+}
+
+func TestTakeBounds(t *testing.T) {
+	now := time.Now()
+
+	// the rates the interval can't hold in nanoseconds are refilled every microsecond
+	for _, rate := range []float64{2e9, 1e12, 0, -1} {
+		limit := types.Limit{Rate: rate, Burst: 2}
+		if interval(limit) != time.Microsecond {
+			t.Errorf("expected an interval of 1µs at %v, got %v", rate, interval(limit))
+		}
+
+		tat, result := take(time.Time{}, now, limit)
+		if !result.Allowed || result.Remaining != 1 {
+			t.Errorf("expected a token at %v, got %+v", rate, result)
+		}
+		if _, result = take(tat, now.Add(time.Microsecond), limit); !result.Allowed {
+			t.Errorf("expected a refilled token at %v, got %+v", rate, result)
+		}
+	}
+
+	if limit := (types.Limit{Rate: types.MaxRate, Burst: 1}); interval(limit) != time.Microsecond {
+		t.Errorf("expected an interval of 1µs at the max rate, got %v", interval(limit))
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	limiter := NewLimiter(map[string]types.Limit{"get": {Rate: 1, Burst: 2}}, nil)
+	ctx := context.Background()
+
+	if _, limited := limiter.Take(ctx, "create", "a"); limited {
+		t.Error("expected the unknown route not to be limited")
+	}
+
+	// the clients have a bucket each
+	for i := 0; i < 2; i++ {
+		if result, _ := limiter.Take(ctx, "get", "a"); !result.Allowed {
+			t.Fatalf("expected request %d to be allowed", i)
+		}
+	}
+	if result, _ := limiter.Take(ctx, "get", "a"); result.Allowed {
+		t.Error("expected the third request to be rejected")
+	}
+	if result, _ := limiter.Take(ctx, "get", "b"); !result.Allowed {
+		t.Error("expected another client to be allowed")
+	}
+}
+
+func TestLimiterStoreFailure(t *testing.T) {
+	server := redistest.NewServer(t, "")
+	server.Close()
+
+	// the limits are applied per instance while the shared store is down
+	store := NewRedisStore(redis.NewClient(redisTypes.ClientParams{Addr: server.Addr(), Timeout: time.Millisecond * 100}), "")
+	limiter := NewLimiter(map[string]types.Limit{"get": {Rate: 1, Burst: 1}}, store)
+	if result, limited := limiter.Take(context.Background(), "get", "a"); !limited || !result.Allowed {
+		t.Errorf("expected the first request to be allowed, got %+v", result)
+	}
+	if result, _ := limiter.Take(context.Background(), "get", "a"); result.Allowed {
+		t.Error("expected the second request to be rejected")
+	}
+}
+
+func TestRedisStore(t *testing.T) {
+	server := redistest.NewServer(t, "")
+
+	// the stand-in runs the algorithm of the script, the tat is kept in microseconds
+	tats := map[string]time.Time{}
+	server.Handle("EVAL", func(args []string) interface{} {
+		if args[2] != "1" {
+			return redis.Error("ERR wrong number of keys")
+		}
+
+		microseconds, _ := strconv.ParseInt(args[4], 10, 64)
+		burst, _ := strconv.Atoi(args[5])
+		limit := types.Limit{Rate: float64(time.Second) / float64(time.Duration(microseconds)*time.Microsecond), Burst: burst}
+
+		now := time.Now()
+		tat, result := take(tats[args[3]], now, limit)
+		tats[args[3]] = tat
+
+		allowed := int64(0)
+		if result.Allowed {
+			allowed = 1
+		}
+
+		return []interface{}{allowed, int64(result.Remaining), result.Reset.Microseconds(), result.RetryAfter.Microseconds()}
+	})
+
+	store := NewRedisStore(redis.NewClient(redisTypes.ClientParams{Addr: server.Addr()}), "test:")
+	limit := types.Limit{Rate: 2, Burst: 2}
+
+	result, err := store.Take(context.Background(), "get:a", limit)
+	if err != nil || !result.Allowed || result.Remaining != 1 || result.Limit != 2 {
+		t.Fatalf("expected an allowed request, got %+v, %v", result, err)
+	}
+	if _, ok := tats["test:get:a"]; !ok {
+		t.Error("expected the prefixed key")
+	}
+
+	_, _ = store.Take(context.Background(), "get:a", limit)
+	result, err = store.Take(context.Background(), "get:a", limit)
+	if err != nil || result.Allowed || result.RetryAfter <= 0 || result.RetryAfter > time.Millisecond*500 {
+		t.Errorf("expected a rejection for up to 500ms, got %+v, %v", result, err)
+	}
+}
+
+func TestClientKey(t *testing.T) {
+	ctx := requestinfo.NewContext(context.Background(), requestinfo.Info{IP: "10.0.0.1"})
+	if key := ClientKey(ctx); key != "ip:10.0.0.1" {
+		t.Errorf("expected the ip, got %s", key)
+	}
+
+	if key := ClientKey(auth.NewContext(ctx, auth.Identity{Subject: "alice", TenantID: "t1"})); key != "sub:t1:alice" {
+		t.Errorf("expected the subject, got %s", key)
+	}
+
+	if key := ClientKey(auth.NewContext(ctx, auth.Identity{Subject: "alice", APIKeyID: "k1"})); key != "apikey:k1" {
+		t.Errorf("expected the api key, got %s", key)
+	}
+
+	// the ip key ignores the identity
+	if key := IPKey(auth.NewContext(ctx, auth.Identity{Subject: "alice", APIKeyID: "k1"})); key != "ip:10.0.0.1" {
+		t.Errorf("expected the ip, got %s", key)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	headers := Headers(types.Result{Limit: 10, Reset: time.Millisecond * 1500, RetryAfter: time.Millisecond * 100})
+	if headers["RateLimit-Limit"] != "10" || headers["RateLimit-Remaining"] != "0" || headers["RateLimit-Reset"] != "2" || headers["Retry-After"] != "1" {
+		t.Errorf("unexpected headers %v", headers)
+	}
+
+	if _, ok := Headers(types.Result{Allowed: true})["Retry-After"]; ok {
+		t.Error("expected no Retry-After when allowed")
+	}
+}
@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"hash/maphash"
+	"sync"
+	"time"
+
+	"gomora/infrastructures/ratelimit/types"
+)
+
+const (
+	memoryStoreShards = 32
+	minSweepSize      = 1024
+)
+
+// MemoryStore keeps the buckets in memory, accurate for a single instance
+// The keys are spread over shards to keep the lock contention low, and the full buckets are swept as the shards grow.
+type MemoryStore struct {
+	seed   maphash.Seed
+	shards [memoryStoreShards]memoryShard
+}
+
+type memoryShard struct {
+	mu        sync.Mutex
+	tats      map[string]time.Time
+	sweepSize int // size triggering the next sweep
+}
+
+// NewMemoryStore returns an empty store
+func NewMemoryStore() *MemoryStore {
+	store := &MemoryStore{seed: maphash.MakeSeed()}
+	for i := range store.shards {
+		store.shards[i].tats = map[string]time.Time{}
+		store.shards[i].sweepSize = minSweepSize
+	}
+
+	return store
+}
+
+// Take takes a token from the bucket of the key
+func (s *MemoryStore) Take(ctx context.Context, key string, limit types.Limit) (types.Result, error) {
+	shard := &s.shards[maphash.String(s.seed, key)%memoryStoreShards]
+	now := time.Now()
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	tat, result := take(shard.tats[key], now, limit)
+	shard.tats[key] = tat
+
+	if len(shard.tats) >= shard.sweepSize {
+		shard.sweep(now)
+	}
+
+	return result, nil
+}
+
+// sweep removes the full buckets, which behave like missing ones
+func (s *memoryShard) sweep(now time.Time) {
+	for key, tat := range s.tats {
+		if !tat.After(now) {
+			delete(s.tats, key)
+		}
+	}
+
+	s.sweepSize = 2 * len(s.tats)
+	if s.sweepSize < minSweepSize {
+		s.sweepSize = minSweepSize
+	}
+}
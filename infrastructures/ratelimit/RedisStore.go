@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gomora/infrastructures/ratelimit/types"
+	"gomora/infrastructures/redis"
+)
+
+// takeScript is the algorithm of take run atomically by redis, on the clock of the server
+// The tat is kept in microseconds and expires once the bucket is full.
+const takeScript = `
+redis.replicate_commands()
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+
+local tat = tonumber(redis.call('GET', KEYS[1])) or now
+if tat < now then
+	tat = now
+end
+
+local new_tat = tat + interval
+local allow_at = new_tat - interval * burst
+if now < allow_at then
+	return {0, 0, tat - now, allow_at - now}
+end
+
+-- formatted as an integer, the default format of the numbers loses precision
+redis.call('SET', KEYS[1], string.format('%.0f', new_tat), 'PX', math.ceil((new_tat - now) / 1000))
+return {1, math.floor((now - allow_at) / interval), new_tat - now, 0}
+`
+
+// RedisStore keeps the buckets in redis, shared by every instance
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStore returns a store prefixing its keys, to share the database with other services
+func NewRedisStore(client *redis.Client, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+// Take takes a token from the bucket of the key
+func (s *RedisStore) Take(ctx context.Context, key string, limit types.Limit) (types.Result, error) {
+	reply, err := s.client.Do(ctx, "EVAL", takeScript, "1", s.prefix+key,
+		strconv.FormatInt(interval(limit).Microseconds(), 10),
+		strconv.Itoa(limit.Burst),
+	)
+	if err != nil {
+		return types.Result{}, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 4 {
+		return types.Result{}, fmt.Errorf("redis: unexpected reply %v", reply)
+	}
+
+	var numbers [4]int64
+	for i, value := range values {
+		if numbers[i], ok = value.(int64); !ok {
+			return types.Result{}, fmt.Errorf("redis: unexpected reply %v", reply)
+		}
+	}
+
+	return types.Result{
+		Allowed:    numbers[0] == 1,
+		Limit:      limit.Burst,
+		Remaining:  int(numbers[1]),
+		Reset:      time.Duration(numbers[2]) * time.Microsecond,
+		RetryAfter: time.Duration(numbers[3]) * time.Microsecond,
+	}, nil
+}
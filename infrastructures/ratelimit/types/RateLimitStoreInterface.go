@@ -0,0 +1,11 @@
+package types
+
+import (
+	"context"
+)
+
+// RateLimitStoreInterface contains the methods of the rate limit stores
+type RateLimitStoreInterface interface {
+	// Take takes a token from the bucket of the key, refilled at the rate of the limit
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
@@ -0,0 +1,23 @@
+package types
+
+import (
+	"time"
+)
+
+// MaxRate is the highest rate of a limit, a token per microsecond, the resolution of the stores
+const MaxRate float64 = 1e6
+
+// Limit is a token bucket of Burst tokens refilled at Rate tokens per second
+type Limit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// Result holds the quota left after taking a token
+type Result struct {
+	Allowed    bool
+	Limit      int           // size of the bucket
+	Remaining  int           // tokens left in the bucket
+	Reset      time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next token, when rejected
+}
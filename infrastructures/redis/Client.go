@@ -0,0 +1,207 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"gomora/infrastructures/redis/types"
+)
+
+const (
+	defaultPoolSize = 16
+	defaultTimeout  = time.Second
+)
+
+// Error is an error reply of the server
+type Error string
+
+func (e Error) Error() string {
+	return "redis: " + string(e)
+}
+
+// Client sends commands to redis, or any server speaking its protocol (RESP)
+// The connections are opened on demand and the idle ones are kept in a bounded pool.
+type Client struct {
+	params types.ClientParams
+	idle   chan *conn
+}
+
+// NewClient returns a client of the server
+func NewClient(params types.ClientParams) *Client {
+	if params.PoolSize <= 0 {
+		params.PoolSize = defaultPoolSize
+	}
+	if params.Timeout <= 0 {
+		params.Timeout = defaultTimeout
+	}
+
+	return &Client{
+		params: params,
+		idle:   make(chan *conn, params.PoolSize),
+	}
+}
+
+// Do sends the command on a pooled connection and returns the reply:
+// a string, an int64, a []byte for the bulk strings, an []interface{} for the arrays, or nil
+func (c *Client) Do(ctx context.Context, args ...string) (interface{}, error) {
+	cn, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := cn.do(ctx, c.params.Timeout, args...)
+
+	// the connection is out of sync after a network error, an error reply leaves it usable
+	var redisErr Error
+	if err != nil && !errors.As(err, &redisErr) {
+		_ = cn.Close()
+		return nil, err
+	}
+
+	select {
+	case c.idle <- cn:
+	default:
+		_ = cn.Close()
+	}
+
+	return reply, err
+}
+
+// Close closes the idle connections
+func (c *Client) Close() error {
+	for {
+		select {
+		case cn := <-c.idle:
+			_ = cn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// conn returns an idle connection, or dials a new one
+func (c *Client) conn(ctx context.Context) (*conn, error) {
+	select {
+	case cn := <-c.idle:
+		return cn, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: c.params.Timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.params.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	cn := &conn{Conn: netConn, reader: bufio.NewReader(netConn), writer: bufio.NewWriter(netConn)}
+	if len(c.params.Password) > 0 {
+		if _, err := cn.do(ctx, c.params.Timeout, "AUTH", c.params.Password); err != nil {
+			_ = cn.Close()
+			return nil, err
+		}
+	}
+	if c.params.DB > 0 {
+		if _, err := cn.do(ctx, c.params.Timeout, "SELECT", strconv.Itoa(c.params.DB)); err != nil {
+			_ = cn.Close()
+			return nil, err
+		}
+	}
+
+	return cn, nil
+}
+
+// conn is a connection speaking the redis protocol
+type conn struct {
+	net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+}
+
+// do writes the command as an array of bulk strings and reads its reply
+func (c *conn) do(ctx context.Context, timeout time.Duration, args ...string) (interface{}, error) {
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := c.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	if err := WriteCommand(c.writer, args...); err != nil {
+		return nil, err
+	}
+	if err := c.writer.Flush(); err != nil {
+		return nil, err
+	}
+
+	return ReadReply(c.reader)
+}
+
+// WriteCommand writes the command as an array of bulk strings
+func WriteCommand(writer io.Writer, args ...string) error {
+	if _, err := fmt.Fprintf(writer, "*%d\r\n", len(args)); err != nil {
+		return err
+	}
+	for _, arg := range args {
+		if _, err := fmt.Fprintf(writer, "$%d\r\n%s\r\n", len(arg), arg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadReply reads a reply: a string, an error, an integer, a bulk string as []byte, an array or nil
+func ReadReply(reader *bufio.Reader) (interface{}, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	kind, payload := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, Error(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(payload)
+		if err != nil || size < 0 {
+			return nil, err
+		}
+
+		value := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, value); err != nil {
+			return nil, err
+		}
+
+		return value[:size], nil
+	case '*':
+		size, err := strconv.Atoi(payload)
+		if err != nil || size < 0 {
+			return nil, err
+		}
+
+		values := make([]interface{}, size)
+		for i := range values {
+			if values[i], err = ReadReply(reader); err != nil {
+				return nil, err
+			}
+		}
+
+		return values, nil
+	default:
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+}
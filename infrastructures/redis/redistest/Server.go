@@ -0,0 +1,216 @@
+// Package redistest provides an in-memory stand-in of redis for the tests
+package redistest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gomora/infrastructures/redis"
+)
+
+// HandlerFunc handles a command and returns its reply:
+// a string, an int64, a []byte, an []interface{}, an error, or nil
+type HandlerFunc func(args []string) interface{}
+
+// Server speaks enough of the redis protocol for the clients of this repository
+// It supports AUTH, SELECT, GET, SET with PX and DEL, other commands are added with Handle.
+type Server struct {
+	listener net.Listener
+	password string
+
+	mu       sync.Mutex
+	entries  map[string]string
+	expires  map[string]time.Time
+	selected map[int]bool
+	handlers map[string]HandlerFunc
+}
+
+// NewServer starts a server requiring the password, if any, closed at the end of the test
+func NewServer(t testing.TB, password string) *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &Server{
+		listener: listener,
+		password: password,
+		entries:  map[string]string{},
+		expires:  map[string]time.Time{},
+		selected: map[int]bool{},
+		handlers: map[string]HandlerFunc{},
+	}
+	t.Cleanup(server.Close)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+// Addr returns the address of the server
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close stops accepting connections
+func (s *Server) Close() {
+	_ = s.listener.Close()
+}
+
+// Handle registers the handler of a command, run with the lock of the server held
+func (s *Server) Handle(command string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[strings.ToUpper(command)] = handler
+}
+
+// Value returns the value of the key, false when missing or expired
+func (s *Server) Value(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.get(key)
+}
+
+// Selected returns true when a client selected the database
+func (s *Server) Selected(db int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.selected[db]
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	authenticated := len(s.password) == 0
+	for {
+		request, err := redis.ReadReply(reader)
+		if err != nil {
+			return
+		}
+
+		var args []string
+		for _, arg := range request.([]interface{}) {
+			args = append(args, string(arg.([]byte)))
+		}
+
+		var reply interface{}
+		switch {
+		case strings.ToUpper(args[0]) == "AUTH":
+			authenticated = args[1] == s.password
+			reply = "OK"
+			if !authenticated {
+				reply = redis.Error("WRONGPASS invalid password")
+			}
+		case !authenticated:
+			reply = redis.Error("NOAUTH Authentication required.")
+		default:
+			reply = s.command(args)
+		}
+
+		if err := writeReply(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) command(args []string) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	command := strings.ToUpper(args[0])
+	if handler, ok := s.handlers[command]; ok {
+		return handler(args)
+	}
+
+	switch command {
+	case "SELECT":
+		db, _ := strconv.Atoi(args[1])
+		s.selected[db] = true
+
+		return "OK"
+	case "GET":
+		if value, ok := s.get(args[1]); ok {
+			return []byte(value)
+		}
+
+		return nil
+	case "SET":
+		s.entries[args[1]] = args[2]
+		delete(s.expires, args[1])
+		if len(args) == 5 && strings.ToUpper(args[3]) == "PX" {
+			milliseconds, _ := strconv.Atoi(args[4])
+			s.expires[args[1]] = time.Now().Add(time.Duration(milliseconds) * time.Millisecond)
+		}
+
+		return "OK"
+	case "DEL":
+		var deleted int64
+		for _, key := range args[1:] {
+			if _, ok := s.entries[key]; ok {
+				delete(s.entries, key)
+				deleted++
+			}
+		}
+
+		return deleted
+	default:
+		return redis.Error("ERR unknown command '" + args[0] + "'")
+	}
+}
+
+func (s *Server) get(key string) (string, bool) {
+	value, ok := s.entries[key]
+	if expires, expiring := s.expires[key]; !ok || (expiring && time.Now().After(expires)) {
+		return "", false
+	}
+
+	return value, true
+}
+
+func writeReply(writer io.Writer, reply interface{}) error {
+	var err error
+	switch reply := reply.(type) {
+	case nil:
+		_, err = fmt.Fprint(writer, "$-1\r\n")
+	case string:
+		_, err = fmt.Fprintf(writer, "+%s\r\n", reply)
+	case error:
+		_, err = fmt.Fprintf(writer, "-%s\r\n", strings.TrimPrefix(reply.Error(), "redis: "))
+	case int64:
+		_, err = fmt.Fprintf(writer, ":%d\r\n", reply)
+	case []byte:
+		_, err = fmt.Fprintf(writer, "$%d\r\n%s\r\n", len(reply), reply)
+	case []interface{}:
+		if _, err = fmt.Fprintf(writer, "*%d\r\n", len(reply)); err != nil {
+			return err
+		}
+		for _, value := range reply {
+			if err = writeReply(writer, value); err != nil {
+				return err
+			}
+		}
+	default:
+		err = fmt.Errorf("redistest: unsupported reply %T", reply)
+	}
+
+	return err
+}
@@ -0,0 +1,14 @@
+package types
+
+import (
+	"time"
+)
+
+// ClientParams holds the settings of the redis client
+type ClientParams struct {
+	Addr     string // host:port
+	Password string
+	DB       int
+	PoolSize int           // idle connections kept open, defaults to 16
+	Timeout  time.Duration // dial, read and write timeout, defaults to 1s
+}
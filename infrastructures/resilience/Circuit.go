@@ -0,0 +1,177 @@
+package resilience
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/afex/hystrix-go/hystrix"
+	metricCollector "github.com/afex/hystrix-go/hystrix/metric_collector"
+	"github.com/afex/hystrix-go/hystrix/rolling"
+)
+
+// the states of a circuit
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half-open" // open, but the next call is let through to test the recovery
+)
+
+// ErrUnknownCircuit is returned for the commands without hystrix config
+var ErrUnknownCircuit = errors.New("unknown circuit")
+
+// CircuitStatus holds the state and the rolling metrics (10s) of a circuit
+type CircuitStatus struct {
+	Name            string
+	State           string
+	ForcedOpen      bool
+	ErrorPercentage int
+	RequestVolume   int
+}
+
+var (
+	forcedOpen sync.Map // command name to true
+	collectors sync.Map // command name to *collector
+)
+
+func init() {
+	// the collectors are created along with the circuits, so they must be registered first
+	metricCollector.Registry.Register(func(name string) metricCollector.MetricCollector {
+		c := &collector{requests: rolling.NewNumber(), errors: rolling.NewNumber()}
+		collectors.Store(name, c)
+
+		return c
+	})
+}
+
+// collector keeps the metrics of a circuit hystrix doesn't expose
+type collector struct {
+	mu             sync.Mutex
+	requests       *rolling.Number
+	errors         *rolling.Number
+	lastExecutedAt time.Time // when the circuit opened or last let a call through
+}
+
+// Update accepts the metrics of an execution
+func (c *collector) Update(r metricCollector.MetricResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests.Increment(r.Attempts)
+	c.errors.Increment(r.Errors)
+	if r.Attempts > 0 && r.ShortCircuits == 0 && r.Rejects == 0 {
+		c.lastExecutedAt = time.Now()
+	}
+}
+
+// Reset resets the metrics, when the circuit closes
+func (c *collector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests = rolling.NewNumber()
+	c.errors = rolling.NewNumber()
+}
+
+// Circuits returns the status of every configured command, sorted by name
+func Circuits() []CircuitStatus {
+	settings := hystrix.GetCircuitSettings()
+
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	circuits := make([]CircuitStatus, 0, len(names))
+	for _, name := range names {
+		circuits = append(circuits, circuitStatus(name, settings[name]))
+	}
+
+	return circuits
+}
+
+// Circuit returns the status of the command
+func Circuit(name string) (CircuitStatus, error) {
+	settings, ok := hystrix.GetCircuitSettings()[name]
+	if !ok {
+		return CircuitStatus{}, ErrUnknownCircuit
+	}
+
+	return circuitStatus(name, settings), nil
+}
+
+// ForceOpen opens the circuit of the command until it is reset, its calls fail fast or fall back
+func ForceOpen(name string) error {
+	if _, ok := hystrix.GetCircuitSettings()[name]; !ok {
+		return ErrUnknownCircuit
+	}
+
+	forcedOpen.Store(name, true)
+
+	return nil
+}
+
+// Reset closes the circuit of the command and clears its metrics
+func Reset(name string) error {
+	if _, ok := hystrix.GetCircuitSettings()[name]; !ok {
+		return ErrUnknownCircuit
+	}
+
+	forcedOpen.Delete(name)
+
+	// hystrix closes an open circuit, resetting its metrics, on the report of a success
+	circuit, _, err := hystrix.GetCircuit(name)
+	if err != nil {
+		return err
+	}
+	if circuit.IsOpen() {
+		return circuit.ReportEvent([]string{"success"}, time.Now(), 0)
+	}
+
+	return nil
+}
+
+// isForcedOpen returns true when the circuit of the command was forced open
+func isForcedOpen(name string) bool {
+	_, ok := forcedOpen.Load(name)
+
+	return ok
+}
+
+func circuitStatus(name string, settings *hystrix.Settings) CircuitStatus {
+	status := CircuitStatus{
+		Name:       name,
+		State:      StateClosed,
+		ForcedOpen: isForcedOpen(name),
+	}
+
+	circuit, _, err := hystrix.GetCircuit(name)
+	if err == nil && circuit.IsOpen() {
+		status.State = StateOpen
+	}
+
+	if value, ok := collectors.Load(name); ok {
+		c := value.(*collector)
+		c.mu.Lock()
+		now := time.Now()
+		requests, errs := c.requests.Sum(now), c.errors.Sum(now)
+		lastExecutedAt := c.lastExecutedAt
+		c.mu.Unlock()
+
+		status.RequestVolume = int(requests)
+		if requests > 0 {
+			status.ErrorPercentage = int(errs/requests*100 + 0.5)
+		}
+		if status.State == StateOpen && now.Sub(lastExecutedAt) > settings.SleepWindow {
+			status.State = StateHalfOpen
+		}
+	}
+
+	if status.ForcedOpen {
+		status.State = StateOpen
+	}
+
+	return status
+}
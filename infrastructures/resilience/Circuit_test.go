@@ -0,0 +1,80 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/afex/hystrix-go/hystrix"
+
+	apiError "gomora/internal/errors"
+)
+
+func TestCircuits(t *testing.T) {
+	hystrix.ConfigureCommand("test_circuit", hystrix.CommandConfig{
+		Timeout:                1000,
+		RequestVolumeThreshold: 5,
+		ErrorPercentThreshold:  50,
+		SleepWindow:            60000,
+	})
+
+	if _, err := Circuit("test_unknown"); !errors.Is(err, ErrUnknownCircuit) {
+		t.Errorf("expected %v, got %v", ErrUnknownCircuit, err)
+	}
+
+	failing := func(ctx context.Context) (int, error) {
+		return 0, errors.New(apiError.DatabaseError)
+	}
+
+	// the failures open the circuit
+	deadline := time.Now().Add(time.Second * 5)
+	for {
+		status, err := Circuit("test_circuit")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.State == StateOpen {
+			if status.ErrorPercentage != 100 || status.RequestVolume < 5 {
+				t.Errorf("unexpected metrics %+v", status)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the circuit never opened: %+v", status)
+		}
+
+		_, _ = Execute(context.Background(), "test_circuit", failing)
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	// the reset closes it
+	if err := Reset("test_circuit"); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := Circuit("test_circuit"); status.State != StateClosed {
+		t.Errorf("expected a closed circuit, got %+v", status)
+	}
+
+	// a forced open circuit fails fast until reset
+	if err := ForceOpen("test_circuit"); err != nil {
+		t.Fatal(err)
+	}
+	called := false
+	_, err := Execute(context.Background(), "test_circuit", func(ctx context.Context) (int, error) {
+		called = true
+
+		return 0, nil
+	})
+	if called || err == nil || err.Error() != apiError.HystrixTimeout {
+		t.Errorf("expected %s without a call, got %v (called %t)", apiError.HystrixTimeout, err, called)
+	}
+	if status, _ := Circuit("test_circuit"); status.State != StateOpen || !status.ForcedOpen {
+		t.Errorf("expected a forced open circuit, got %+v", status)
+	}
+
+	_ = Reset("test_circuit")
+	if _, err := Execute(context.Background(), "test_circuit", func(ctx context.Context) (int, error) { return 0, nil }); err != nil {
+		t.Errorf("expected the circuit to be reset, got %v", err)
+	}
+}
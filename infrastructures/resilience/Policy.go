@@ -0,0 +1,63 @@
+package resilience
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+const (
+	defaultBaseDelay = time.Millisecond * 50
+	defaultMaxDelay  = time.Second
+)
+
+// RetryPolicy holds the retry settings of the infrastructure failures
+// Zero MaxAttempts disables the retries, zero delays fall back to a backoff from 50ms up to 1s.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// Option configures the policies of an execution
+type Option func(*options)
+
+type options struct {
+	retry    RetryPolicy
+	fallback interface{}
+}
+
+// WithRetry retries the infrastructure failures with an exponential backoff
+// The open circuits and the rejected calls are not retried.
+func WithRetry(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = policy
+	}
+}
+
+// WithFallback answers the call when it still fails on an infrastructure failure, like with a cached value
+// The fallback must return the type of the execution.
+func WithFallback[T any](fallback func(ctx context.Context, err error) (T, error)) Option {
+	return func(o *options) {
+		o.fallback = fallback
+	}
+}
+
+// backoff returns the delay before the attempt, with full jitter
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	baseDelay := p.BaseDelay
+	if baseDelay <= 0 {
+		baseDelay = defaultBaseDelay
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxDelay
+	}
+
+	delay := maxDelay
+	if shift := attempt - 1; shift < 20 && baseDelay<<shift < maxDelay {
+		delay = baseDelay << shift
+	}
+
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
@@ -0,0 +1,130 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+
+	"github.com/afex/hystrix-go/hystrix"
+
+	apiError "gomora/internal/errors"
+)
+
+// ErrPanic is returned when the function of a command panicked, it counts toward the circuit but isn't retried
+var ErrPanic = errors.New("command panicked")
+
+type outcome[T any] struct {
+	value T
+	err   error
+}
+
+// Execute runs the function through the resilience policies of the named command
+// The timeout, the circuit breaker and the bulkhead (max concurrent requests) follow the hystrix config of the command,
+// the work is cancelled through its context when the timeout expires. Only the infrastructure failures count toward
+// the circuit and are retried; the timeouts, open circuits and rejections are reported as HYSTRIX_TIMEOUT.
+// The deadline of the context bounds the command as well: the retries it can't afford are skipped, and once it
+// expires the failures are reported as REQUEST_TIMEOUT without counting toward the circuit.
+func Execute[T any](ctx context.Context, name string, fn func(ctx context.Context) (T, error), opts ...Option) (T, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// the caller already gave up, the command is not attempted
+	if ctx.Err() != nil {
+		var zero T
+		return zero, errors.New(apiError.RequestTimeout)
+	}
+
+	value, err := run(ctx, name, fn)
+	for attempt := 1; attempt < o.retry.MaxAttempts && retriable(ctx, err); attempt++ {
+		delay := o.retry.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(delay):
+			value, err = run(ctx, name, fn)
+		}
+	}
+
+	var circuitErr hystrix.CircuitError
+	switch {
+	case ctx.Err() != nil && apiError.IsInfrastructureError(err):
+		err = errors.New(apiError.RequestTimeout)
+	case errors.As(err, &circuitErr):
+		err = errors.New(apiError.HystrixTimeout)
+	}
+
+	if o.fallback != nil && apiError.IsInfrastructureError(err) {
+		fallback, ok := o.fallback.(func(ctx context.Context, err error) (T, error))
+		if !ok {
+			panic(fmt.Sprintf("resilience: the fallback of %s doesn't return %T", name, value))
+		}
+
+		return fallback(ctx, err)
+	}
+
+	return value, err
+}
+
+// run makes a single attempt through the circuit breaker
+func run[T any](ctx context.Context, name string, fn func(ctx context.Context) (T, error)) (T, error) {
+	if isForcedOpen(name) {
+		var zero T
+		return zero, hystrix.ErrCircuitOpen
+	}
+
+	// cancels the work still running once the command timed out
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result := make(chan outcome[T], 1)
+	err := hystrix.DoC(ctx, name, func(ctx context.Context) (err error) {
+		// fn runs on the goroutine of hystrix, a panic would crash the server instead of reaching the recoverers
+		defer func() {
+			if p := recover(); p != nil {
+				log.Printf("[RESILIENCE] %s panicked: %v\n%s", name, p, debug.Stack())
+				err = fmt.Errorf("%w: %s: %v", ErrPanic, name, p)
+				result <- outcome[T]{err: err}
+			}
+		}()
+
+		value, err := fn(ctx)
+		result <- outcome[T]{value, err}
+
+		// the client errors are results, not failures of the command
+		if !apiError.IsInfrastructureError(err) {
+			return nil
+		}
+
+		// neither are the failures once the caller gave up, hystrix doesn't count its context errors
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		return err
+	}, nil)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	r := <-result
+
+	return r.value, r.err
+}
+
+// retriable returns true for the infrastructure failures worth another attempt
+func retriable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || !apiError.IsInfrastructureError(err) {
+		return false
+	}
+
+	return !errors.Is(err, hystrix.ErrCircuitOpen) && !errors.Is(err, hystrix.ErrMaxConcurrency) && !errors.Is(err, ErrPanic)
+}
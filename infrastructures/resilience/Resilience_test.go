@@ -0,0 +1,210 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/afex/hystrix-go/hystrix"
+
+	apiError "gomora/internal/errors"
+)
+
+func TestExecute(t *testing.T) {
+	value, err := Execute(context.Background(), "test_execute", func(ctx context.Context) (string, error) {
+		return "value", nil
+	})
+	if value != "value" || err != nil {
+		t.Errorf("unexpected result %q, %v", value, err)
+	}
+
+	// the client errors are returned as is
+	_, err = Execute(context.Background(), "test_execute", func(ctx context.Context) (string, error) {
+		return "", errors.New(apiError.MissingRecord)
+	})
+	if err == nil || err.Error() != apiError.MissingRecord {
+		t.Errorf("expected %s, got %v", apiError.MissingRecord, err)
+	}
+}
+
+func TestExecuteTimeout(t *testing.T) {
+	hystrix.ConfigureCommand("test_timeout", hystrix.CommandConfig{Timeout: 20})
+
+	cancelled := make(chan struct{})
+	_, err := Execute(context.Background(), "test_timeout", func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		close(cancelled)
+
+		return 0, ctx.Err()
+	})
+	if err == nil || err.Error() != apiError.HystrixTimeout {
+		t.Errorf("expected %s, got %v", apiError.HystrixTimeout, err)
+	}
+
+	// the work left running is cancelled
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("the work was not cancelled")
+	}
+}
+
+func TestExecuteRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	var attempts atomic.Int32
+	value, err := Execute(context.Background(), "test_retry", func(ctx context.Context) (int, error) {
+		if attempts.Add(1) < 3 {
+			return 0, errors.New(apiError.DatabaseError)
+		}
+
+		return 42, nil
+	}, WithRetry(policy))
+	if value != 42 || err != nil || attempts.Load() != 3 {
+		t.Errorf("unexpected result %d, %v after %d attempts", value, err, attempts.Load())
+	}
+
+	// the client errors are not retried
+	attempts.Store(0)
+	_, _ = Execute(context.Background(), "test_retry", func(ctx context.Context) (int, error) {
+		attempts.Add(1)
+
+		return 0, errors.New(apiError.DuplicateRecord)
+	}, WithRetry(policy))
+	if attempts.Load() != 1 {
+		t.Errorf("expected a single attempt, got %d", attempts.Load())
+	}
+}
+
+func TestExecuteFallback(t *testing.T) {
+	fallback := WithFallback(func(ctx context.Context, err error) (string, error) {
+		return "fallback", nil
+	})
+
+	value, err := Execute(context.Background(), "test_fallback", func(ctx context.Context) (string, error) {
+		return "", errors.New(apiError.DatabaseError)
+	}, fallback)
+	if value != "fallback" || err != nil {
+		t.Errorf("unexpected result %q, %v", value, err)
+	}
+
+	// the client errors don't fall back
+	_, err = Execute(context.Background(), "test_fallback", func(ctx context.Context) (string, error) {
+		return "", errors.New(apiError.MissingRecord)
+	}, fallback)
+	if err == nil || err.Error() != apiError.MissingRecord {
+		t.Errorf("expected %s, got %v", apiError.MissingRecord, err)
+	}
+}
+
+func TestExecuteBulkhead(t *testing.T) {
+	hystrix.ConfigureCommand("test_bulkhead", hystrix.CommandConfig{Timeout: 1000, MaxConcurrentRequests: 1})
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		_, _ = Execute(context.Background(), "test_bulkhead", func(ctx context.Context) (int, error) {
+			close(started)
+			<-release
+
+			return 0, nil
+		})
+	}()
+	<-started
+	defer close(release)
+
+	_, err := Execute(context.Background(), "test_bulkhead", func(ctx context.Context) (int, error) {
+		return 0, nil
+	})
+	if err == nil || err.Error() != apiError.HystrixTimeout {
+		t.Errorf("expected %s, got %v", apiError.HystrixTimeout, err)
+	}
+}
+
+func TestExecuteDeadline(t *testing.T) {
+	hystrix.ConfigureCommand("test_deadline", hystrix.CommandConfig{Timeout: 1000, RequestVolumeThreshold: 1, ErrorPercentThreshold: 1})
+
+	// the failures after the caller gave up are not the dependency's
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		_, err := Execute(ctx, "test_deadline", func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+
+			return 0, errors.New(apiError.DatabaseError)
+		})
+		cancel()
+		if err == nil || err.Error() != apiError.RequestTimeout {
+			t.Fatalf("expected %s, got %v", apiError.RequestTimeout, err)
+		}
+	}
+
+	time.Sleep(time.Millisecond * 50)
+	if circuit, _, _ := hystrix.GetCircuit("test_deadline"); circuit.IsOpen() {
+		t.Error("expected the circuit to stay closed")
+	}
+
+	// the caller who already gave up is not served
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Execute(ctx, "test_deadline", func(ctx context.Context) (int, error) {
+		t.Error("unexpected attempt")
+
+		return 0, nil
+	})
+	if err == nil || err.Error() != apiError.RequestTimeout {
+		t.Errorf("expected %s, got %v", apiError.RequestTimeout, err)
+	}
+}
+
+func TestExecuteRetryDeadline(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour, MaxDelay: time.Hour}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	// the retry doesn't fit in the budget left
+	var attempts atomic.Int32
+	_, err := Execute(ctx, "test_retry_deadline", func(ctx context.Context) (int, error) {
+		attempts.Add(1)
+
+		return 0, errors.New(apiError.DatabaseError)
+	}, WithRetry(policy))
+	if err == nil || err.Error() != apiError.DatabaseError || attempts.Load() != 1 {
+		t.Errorf("expected a single attempt, got %v after %d attempts", err, attempts.Load())
+	}
+}
+
+func TestExecutePanic(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	var attempts atomic.Int32
+	_, err := Execute(context.Background(), "test_panic", func(ctx context.Context) (int, error) {
+		attempts.Add(1)
+		panic("boom")
+	}, WithRetry(policy))
+	if !errors.Is(err, ErrPanic) {
+		t.Fatalf("expected %v, got %v", ErrPanic, err)
+	}
+
+	// the panics are bugs, they are not retried
+	if n := attempts.Load(); n != 1 {
+		t.Errorf("expected a single attempt, got %d", n)
+	}
+
+	// but they count toward the circuit
+	deadline := time.Now().Add(time.Second * 5)
+	for {
+		status, err := Circuit("test_panic")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.ErrorPercentage == 100 && status.RequestVolume >= 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the panic was not counted: %+v", status)
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+}
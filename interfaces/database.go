@@ -0,0 +1,147 @@
+package interfaces
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gomora/infrastructures/database"
+	"gomora/infrastructures/database/mysql"
+	mysqlTypes "gomora/infrastructures/database/mysql/types"
+	"gomora/infrastructures/database/postgres"
+	postgresTypes "gomora/infrastructures/database/postgres/types"
+	"gomora/infrastructures/database/sqlite"
+	sqliteTypes "gomora/infrastructures/database/sqlite/types"
+	dbTypes "gomora/infrastructures/database/types"
+)
+
+// ConnectDatabase opens the database selected by DB_DRIVER, mysql by default, and returns its schema migrator
+// The sqlite database is opened at the DB_DATABASE path, in memory when empty.
+func ConnectDatabase() (dbTypes.DBHandlerInterface, *database.Migrator, error) {
+	retryMaxAttempts, _ := strconv.Atoi(os.Getenv("DB_RETRY_MAX_ATTEMPTS"))
+	retry := dbTypes.RetryPolicy{
+		MaxAttempts: retryMaxAttempts,
+		BaseDelay:   envDuration("DB_RETRY_BASE_DELAY"),
+		MaxDelay:    envDuration("DB_RETRY_MAX_DELAY"),
+	}
+
+	maxOpenConns, _ := strconv.Atoi(os.Getenv("DB_MAX_OPEN_CONNS"))
+	maxIdleConns, _ := strconv.Atoi(os.Getenv("DB_MAX_IDLE_CONNS"))
+	pool := dbTypes.PoolParams{
+		MaxOpenConns:    maxOpenConns,
+		MaxIdleConns:    maxIdleConns,
+		ConnMaxLifetime: envDuration("DB_CONN_MAX_LIFETIME"),
+		ConnMaxIdleTime: envDuration("DB_CONN_MAX_IDLE_TIME"),
+	}
+
+	// extra dsn options, like charset=utf8mb4&loc=UTC
+	options := map[string]string{}
+	if values, err := url.ParseQuery(os.Getenv("DB_DSN_OPTIONS")); err == nil {
+		for key := range values {
+			options[key] = values.Get(key)
+		}
+	}
+
+	var replicaDSNs []string
+	for _, dsn := range strings.Split(os.Getenv("DB_REPLICA_DSNS"), ",") {
+		if dsn = strings.TrimSpace(dsn); len(dsn) > 0 {
+			replicaDSNs = append(replicaDSNs, dsn)
+		}
+	}
+
+	switch driver := os.Getenv("DB_DRIVER"); driver {
+	case "", "mysql":
+		handler := &mysql.MySQLDBHandler{}
+		params := mysqlTypes.ConnectionParams{
+			DBHost:             os.Getenv("DB_HOST"),
+			DBPort:             os.Getenv("DB_PORT"),
+			DBDatabase:         os.Getenv("DB_DATABASE"),
+			DBUsername:         os.Getenv("DB_USERNAME"),
+			DBPassword:         os.Getenv("DB_PASSWORD"),
+			Retry:              retry,
+			Pool:               pool,
+			TLS:                os.Getenv("DB_TLS"),
+			TLSCAFile:          os.Getenv("DB_TLS_CA"),
+			TLSCertFile:        os.Getenv("DB_TLS_CERT"),
+			TLSKeyFile:         os.Getenv("DB_TLS_KEY"),
+			Timeout:            envDuration("DB_TIMEOUT"),
+			ReadTimeout:        envDuration("DB_READ_TIMEOUT"),
+			WriteTimeout:       envDuration("DB_WRITE_TIMEOUT"),
+			Options:            options,
+			SlowQueryThreshold: envDuration("DB_SLOW_QUERY_THRESHOLD"),
+			ReplicaDSNs:        replicaDSNs,
+		}
+
+		var err error
+		if len(os.Getenv("DB_SSH_HOST")) > 0 {
+			// the database is only reachable through an ssh tunnel
+			err = handler.ConnectViaSSH(mysqlTypes.SSHConnectionParams{
+				SSHHost:           os.Getenv("DB_SSH_HOST"),
+				SSHPort:           os.Getenv("DB_SSH_PORT"),
+				SSHUsername:       os.Getenv("DB_SSH_USER"),
+				SSHPassword:       os.Getenv("DB_SSH_PASSWORD"),
+				SSHKeyFile:        os.Getenv("DB_SSH_KEY_FILE"),
+				SSHKeyPassphrase:  os.Getenv("DB_SSH_KEY_PASSPHRASE"),
+				SSHKnownHostsFile: os.Getenv("DB_SSH_KNOWN_HOSTS"),
+				SSHKeepAlive:      envDuration("DB_SSH_KEEPALIVE"),
+			}, params)
+		} else {
+			err = handler.Connect(params)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		migrator, err := mysql.NewMigrator(handler.Conn)
+
+		return handler, migrator, err
+	case "postgres":
+		handler := &postgres.PostgresDBHandler{}
+		err := handler.Connect(postgresTypes.ConnectionParams{
+			DBHost:             os.Getenv("DB_HOST"),
+			DBPort:             os.Getenv("DB_PORT"),
+			DBDatabase:         os.Getenv("DB_DATABASE"),
+			DBUsername:         os.Getenv("DB_USERNAME"),
+			DBPassword:         os.Getenv("DB_PASSWORD"),
+			SSLMode:            os.Getenv("DB_SSL_MODE"),
+			Retry:              retry,
+			Pool:               pool,
+			Options:            options,
+			SlowQueryThreshold: envDuration("DB_SLOW_QUERY_THRESHOLD"),
+			ReplicaDSNs:        replicaDSNs,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		migrator, err := postgres.NewMigrator(handler.Conn)
+
+		return handler, migrator, err
+	case "sqlite":
+		handler := &sqlite.SQLiteDBHandler{}
+		err := handler.Connect(sqliteTypes.ConnectionParams{
+			DBPath:             os.Getenv("DB_DATABASE"),
+			Retry:              retry,
+			SlowQueryThreshold: envDuration("DB_SLOW_QUERY_THRESHOLD"),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		migrator, err := sqlite.NewMigrator(handler.Conn)
+
+		return handler, migrator, err
+	default:
+		return nil, nil, fmt.Errorf("unsupported DB_DRIVER %q", driver)
+	}
+}
+
+// envDuration parses the duration of the environment variable, 0 when unset or invalid
+func envDuration(key string) time.Duration {
+	duration, _ := time.ParseDuration(os.Getenv(key))
+
+	return duration
+}
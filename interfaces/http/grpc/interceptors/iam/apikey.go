@@ -0,0 +1,73 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"gomora/internal/auth"
+	"gomora/internal/errors"
+	"gomora/module/apikey/application"
+	serviceTypes "gomora/module/apikey/infrastructure/service/types"
+	auditApplication "gomora/module/audit/application"
+)
+
+// APIKeyMetadataKey is the metadata key carrying the api key
+const APIKeyMetadataKey string = "x-api-key"
+
+// APIKeyAuthInterceptor authenticates the calls carrying an api key
+// Calls without the metadata are left to the jwt authentication, rejected keys are recorded to the audit log.
+func APIKeyAuthInterceptor(service application.APIKeyQueryServiceInterface, auditor auditApplication.AuditEventCommandServiceInterface) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok || len(md.Get(APIKeyMetadataKey)) == 0 {
+			return handler(ctx, req)
+		}
+
+		identity, err := service.AuthenticateAPIKey(ctx, serviceTypes.AuthenticateAPIKey{
+			Key: md.Get(APIKeyMetadataKey)[0],
+			IP:  peerIP(ctx),
+		})
+		if err != nil {
+			var code codes.Code
+
+			switch err.Error() {
+			case errors.UnauthorizedAccess:
+				code = codes.Unauthenticated
+			case errors.ForbiddenAccess:
+				code = codes.PermissionDenied
+			default:
+				code = codes.Internal
+			}
+
+			auditAuthFailure(ctx, auditor, info, err.Error())
+
+			st := status.New(code, fmt.Sprintf("[AUTH] %s", err.Error()))
+
+			return nil, st.Err()
+		}
+
+		return handler(auth.NewContext(ctx, identity), req)
+	}
+}
+
+// peerIP returns the ip of the calling peer
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
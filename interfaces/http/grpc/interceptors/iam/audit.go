@@ -0,0 +1,21 @@
+package jwt
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	auditApplication "gomora/module/audit/application"
+	"gomora/module/audit/domain/entity"
+	auditTypes "gomora/module/audit/infrastructure/service/types"
+)
+
+// auditAuthFailure records the rejected authentication of the call
+func auditAuthFailure(ctx context.Context, auditor auditApplication.AuditEventCommandServiceInterface, info *grpc.UnaryServerInfo, errorCode string) {
+	_ = auditor.RecordAuditEvent(ctx, auditTypes.RecordAuditEvent{
+		Action:     entity.ActionAuthFailure,
+		ResourceID: info.FullMethod,
+		Outcome:    entity.OutcomeFailure,
+		ErrorCode:  errorCode,
+	})
+}
@@ -0,0 +1,81 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-chi/jwtauth/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"gomora/internal/auth"
+	"gomora/internal/errors"
+	auditApplication "gomora/module/audit/application"
+)
+
+// publicMethods are served without authentication, like the health checks of the load balancers
+var publicMethods = map[string]bool{
+	"/grpc.health.v1.Health/Check": true,
+}
+
+// JWTAuthInterceptor verifies the bearer token from the authorization metadata
+// and passes the caller identity to the handler context, rejected tokens are recorded to the audit log
+func JWTAuthInterceptor(tokenAuth *jwtauth.JWTAuth, auditor auditApplication.AuditEventCommandServiceInterface) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		// already authenticated by an api key
+		if _, ok := auth.FromContext(ctx); ok || publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		identity, ok := verifyToken(ctx, tokenAuth)
+		if !ok {
+			auditAuthFailure(ctx, auditor, info, errors.UnauthorizedAccess)
+
+			st := status.New(codes.Unauthenticated, fmt.Sprintf("[AUTH] %s", errors.UnauthorizedAccess))
+
+			return nil, st.Err()
+		}
+
+		return handler(auth.NewContext(ctx, identity), req)
+	}
+}
+
+// verifyToken verifies the bearer token of the call and returns the identity it carries
+func verifyToken(ctx context.Context, tokenAuth *jwtauth.JWTAuth) (auth.Identity, bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	bearer := bearerToken(md)
+	if len(bearer) == 0 {
+		return auth.Identity{}, false
+	}
+
+	token, err := jwtauth.VerifyToken(tokenAuth, bearer)
+	if err != nil {
+		return auth.Identity{}, false
+	}
+
+	claims, err := token.AsMap(ctx)
+	if err != nil {
+		return auth.Identity{}, false
+	}
+
+	// tokens without a subject cannot own records
+	identity := auth.IdentityFromClaims(claims)
+	if len(identity.Subject) == 0 {
+		return auth.Identity{}, false
+	}
+
+	return identity, true
+}
+
+// bearerToken returns the bearer token from the authorization metadata
+func bearerToken(md metadata.MD) string {
+	values := md.Get("authorization")
+	if len(values) == 0 || len(values[0]) < 7 || !strings.EqualFold(values[0][:7], "BEARER ") {
+		return ""
+	}
+
+	return values[0][7:]
+}
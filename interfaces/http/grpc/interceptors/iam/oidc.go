@@ -0,0 +1,57 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"gomora/infrastructures/oidc"
+	"gomora/infrastructures/oidc/types"
+	"gomora/internal/auth"
+	"gomora/internal/errors"
+	auditApplication "gomora/module/audit/application"
+)
+
+// OIDCAuthInterceptor authenticates the bearer tokens issued by the external OpenID Connect provider
+// Tokens from other issuers are left to the jwt authentication, a nil provider disables the interceptor.
+// Rejected tokens are recorded to the audit log.
+func OIDCAuthInterceptor(provider types.OIDCProviderInterface, auditor auditApplication.AuditEventCommandServiceInterface) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if provider == nil {
+			return handler(ctx, req)
+		}
+
+		// already authenticated by an api key
+		if _, ok := auth.FromContext(ctx); ok {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		token := bearerToken(md)
+		if !oidc.IssuedBy(token, provider.Issuer()) {
+			return handler(ctx, req)
+		}
+
+		identity, err := provider.Verify(ctx, token)
+		if err != nil {
+			var st *status.Status
+
+			switch err {
+			case oidc.ErrInvalidToken:
+				st = status.New(codes.Unauthenticated, fmt.Sprintf("[AUTH] %s", errors.UnauthorizedAccess))
+				auditAuthFailure(ctx, auditor, info, errors.UnauthorizedAccess)
+			default:
+				st = status.New(codes.Unavailable, fmt.Sprintf("[AUTH] %s", errors.ServerError))
+				auditAuthFailure(ctx, auditor, info, errors.ServerError)
+			}
+
+			return nil, st.Err()
+		}
+
+		return handler(auth.NewContext(ctx, identity), req)
+	}
+}
@@ -0,0 +1,50 @@
+package loadshed
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gomora/infrastructures/loadshed"
+	"gomora/internal/errors"
+)
+
+// LoadShedInterceptor sheds the calls beyond the concurrency limit with RESOURCE_EXHAUSTED
+// The priorities are keyed by full method name, the unlisted methods are writes.
+func LoadShedInterceptor(limiter *loadshed.Limiter, priorities map[string]loadshed.Priority) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if limiter == nil {
+			return handler(ctx, req)
+		}
+
+		priority, ok := priorities[info.FullMethod]
+		if !ok {
+			priority = loadshed.PriorityWrite
+		}
+
+		release, ok := limiter.Acquire(priority)
+		if !ok {
+			st := status.New(codes.ResourceExhausted, fmt.Sprintf("[LOADSHED] %s", errors.ServerOverloaded))
+
+			return nil, st.Err()
+		}
+
+		failed := true
+		defer func() {
+			release(failed)
+		}()
+
+		// like over REST, the rejections are not failures and the slow calls are counted by their latency
+		resp, err := handler(ctx, req)
+		switch status.Code(err) {
+		case codes.Internal, codes.Unknown:
+		default:
+			failed = false
+		}
+
+		return resp, err
+	}
+}
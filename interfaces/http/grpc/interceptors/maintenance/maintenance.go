@@ -0,0 +1,33 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gomora/infrastructures/maintenance"
+	"gomora/internal/errors"
+)
+
+// MaintenanceInterceptor rejects the calls during maintenance with UNAVAILABLE: all of them in full maintenance,
+// all but the reads, keyed by full method name, in read-only maintenance. The health checks are always served.
+func MaintenanceInterceptor(reads map[string]bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		current := maintenance.Current()
+		if strings.HasPrefix(info.FullMethod, "/grpc.health.v1.Health/") || !current.BlocksWrites() {
+			return handler(ctx, req)
+		}
+
+		if current.BlocksReads() || !reads[info.FullMethod] {
+			st := status.New(codes.Unavailable, fmt.Sprintf("[MAINTENANCE] %s", errors.ServerMaintenance))
+
+			return nil, st.Err()
+		}
+
+		return handler(ctx, req)
+	}
+}
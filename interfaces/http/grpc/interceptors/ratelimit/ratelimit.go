@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"gomora/infrastructures/ratelimit"
+	"gomora/internal/errors"
+)
+
+// RateLimitInterceptor limits the calls of every client on the routes of the methods, keyed by full method name
+// The quota is sent in the ratelimit-* header metadata. It must run after the authentication interceptors.
+func RateLimitInterceptor(limiter *ratelimit.Limiter, routes map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		route, ok := routes[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		return rateLimit(ctx, limiter, route, ratelimit.ClientKey(ctx), req, handler)
+	}
+}
+
+// IPRateLimitInterceptor limits the calls of every client ip on the route, except the exempted methods
+// It runs before the authentication interceptors, so that the floods of rejected credentials are turned away
+// before they are checked and audited.
+func IPRateLimitInterceptor(limiter *ratelimit.Limiter, route string, exempt ...string) grpc.UnaryServerInterceptor {
+	exempted := map[string]bool{}
+	for _, method := range exempt {
+		exempted[method] = true
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if exempted[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		return rateLimit(ctx, limiter, route, ratelimit.IPKey(ctx), req, handler)
+	}
+}
+
+// rateLimit limits the calls of the client on the route
+func rateLimit(ctx context.Context, limiter *ratelimit.Limiter, route, client string, req interface{}, handler grpc.UnaryHandler) (interface{}, error) {
+	result, limited := limiter.Take(ctx, route, client)
+	if !limited {
+		return handler(ctx, req)
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.New(ratelimit.Headers(result)))
+
+	if !result.Allowed {
+		st := status.New(codes.ResourceExhausted, fmt.Sprintf("[RATELIMIT] %s", errors.MaximumLimitReached))
+
+		return nil, st.Err()
+	}
+
+	return handler(ctx, req)
+}
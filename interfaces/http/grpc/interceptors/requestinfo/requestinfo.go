@@ -0,0 +1,42 @@
+package requestinfo
+
+import (
+	"context"
+	"net"
+
+	"github.com/segmentio/ksuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"gomora/internal/requestinfo"
+)
+
+// RequestIDMetadataKey is the metadata key carrying the request id
+const RequestIDMetadataKey string = "x-request-id"
+
+// RequestInfoInterceptor passes the request id and the peer ip to the call context
+// A request id is generated when the caller did not send one.
+func RequestInfoInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	requestInfo := requestinfo.Info{}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDMetadataKey); len(values) > 0 {
+			requestInfo.RequestID = values[0]
+		}
+	}
+	if len(requestInfo.RequestID) == 0 {
+		requestInfo.RequestID = ksuid.New().String()
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		ip, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			ip = p.Addr.String()
+		}
+
+		requestInfo.IP = ip
+	}
+
+	return handler(requestinfo.NewContext(ctx, requestInfo), req)
+}
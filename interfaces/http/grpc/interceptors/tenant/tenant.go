@@ -0,0 +1,44 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"gomora/internal/auth"
+	"gomora/internal/errors"
+	"gomora/internal/tenant"
+)
+
+// TenantInterceptor resolves the tenant of the call from the token claim, the default tenant without one
+// An x-tenant-id metadata naming another tenant is rejected. It must run after the authentication interceptor.
+func TenantInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	identity, _ := auth.FromContext(ctx)
+
+	var requestedTenantID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(tenant.MetadataKey); len(values) > 0 {
+			requestedTenantID = values[0]
+		}
+	}
+
+	tenantID, err := tenant.Resolve(identity.TenantID, requestedTenantID)
+	if err != nil {
+		var st *status.Status
+
+		switch err {
+		case tenant.ErrInvalidTenant:
+			st = status.New(codes.InvalidArgument, fmt.Sprintf("[TENANT] %s", errors.InvalidRequestPayload))
+		default:
+			st = status.New(codes.PermissionDenied, fmt.Sprintf("[TENANT] %s", errors.ForbiddenAccess))
+		}
+
+		return nil, st.Err()
+	}
+
+	return handler(tenant.NewContext(ctx, tenantID), req)
+}
@@ -4,11 +4,26 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"os"
 	"sync"
+	"time"
 
+	"github.com/go-chi/jwtauth/v5"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthPB "google.golang.org/grpc/health/grpc_health_v1"
 
+	ratelimit_config "gomora/configs/ratelimit"
+	timeout_config "gomora/configs/timeout"
+	"gomora/infrastructures/loadshed"
 	"gomora/interfaces"
+	jwt "gomora/interfaces/http/grpc/interceptors/iam"
+	loadshedInterceptor "gomora/interfaces/http/grpc/interceptors/loadshed"
+	"gomora/interfaces/http/grpc/interceptors/maintenance"
+	"gomora/interfaces/http/grpc/interceptors/ratelimit"
+	"gomora/interfaces/http/grpc/interceptors/requestinfo"
+	"gomora/interfaces/http/grpc/interceptors/tenant"
+	"gomora/interfaces/http/grpc/interceptors/timeout"
 	recordGRPCPB "gomora/module/record/interfaces/http/grpc/pb"
 )
 
@@ -31,14 +46,55 @@ func (s *server) Serve(port int) {
 		log.Fatalf("[SERVER] gRPC server failed %v", err)
 	}
 
+	auditor := interfaces.ServiceContainer().RegisterAuditor()
+	tokenAuth := jwtauth.New("HS256", []byte(os.Getenv("JWT_SECRET")), nil)
+
+	// the rate limited routes of the methods
+	rateLimitRoutes := map[string]string{
+		"/record.RecordCommandService/CreateRecord": ratelimit_config.CreateRecord,
+		"/record.RecordQueryService/GetRecordByID":  ratelimit_config.GetRecord,
+	}
+
+	// the priorities of the methods under load, the unlisted ones are writes
+	loadShedPriorities := map[string]loadshed.Priority{
+		"/grpc.health.v1.Health/Check":             loadshed.PriorityCritical,
+		"/record.RecordQueryService/GetRecordByID": loadshed.PriorityRead,
+	}
+
+	// the timeouts of the methods, the clients can ask for less with grpc-timeout
+	requestTimeouts := interfaces.ServiceContainer().RegisterRequestTimeouts()
+	timeouts := map[string]time.Duration{
+		"/record.RecordCommandService/CreateRecord": requestTimeouts[timeout_config.CreateRecord],
+		"/record.RecordQueryService/GetRecordByID":  requestTimeouts[timeout_config.GetRecord],
+	}
+
+	// the methods still served in read-only maintenance
+	maintenanceReads := map[string]bool{
+		"/record.RecordQueryService/GetRecordByID": true,
+	}
+
 	// create grpc server
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			requestinfo.RequestInfoInterceptor,
+			timeout.TimeoutInterceptor(timeouts),
+			loadshedInterceptor.LoadShedInterceptor(interfaces.ServiceContainer().RegisterLoadShedder(), loadShedPriorities),
+			ratelimit.IPRateLimitInterceptor(interfaces.ServiceContainer().RegisterRateLimiter(), ratelimit_config.IP, "/grpc.health.v1.Health/Check"),
+			maintenance.MaintenanceInterceptor(maintenanceReads),
+			jwt.APIKeyAuthInterceptor(interfaces.ServiceContainer().RegisterAPIKeyAuthenticator(), auditor),
+			jwt.OIDCAuthInterceptor(interfaces.ServiceContainer().RegisterOIDCProvider(), auditor),
+			jwt.JWTAuthInterceptor(tokenAuth, auditor),
+			ratelimit.RateLimitInterceptor(interfaces.ServiceContainer().RegisterRateLimiter(), rateLimitRoutes),
+			tenant.TenantInterceptor,
+		),
+	)
 
 	recordCommandServer := interfaces.ServiceContainer().RegisterRecordGRPCCommandController()
 	recordQueryServer := interfaces.ServiceContainer().RegisterRecordGRPCQueryController()
 
 	recordGRPCPB.RegisterRecordCommandServiceServer(grpcServer, &recordCommandServer)
 	recordGRPCPB.RegisterRecordQueryServiceServer(grpcServer, &recordQueryServer)
+	healthPB.RegisterHealthServer(grpcServer, health.NewServer())
 
 	log.Printf("[SERVER] gRPC server running on :%d", port)
 	if err := grpcServer.Serve(lis); err != nil {
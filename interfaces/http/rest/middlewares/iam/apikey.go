@@ -0,0 +1,68 @@
+package jwt
+
+import (
+	"net/http"
+
+	"gomora/interfaces/http/rest/viewmodels"
+	"gomora/internal/auth"
+	"gomora/internal/errors"
+	"gomora/internal/requestinfo"
+	"gomora/module/apikey/application"
+	serviceTypes "gomora/module/apikey/infrastructure/service/types"
+	auditApplication "gomora/module/audit/application"
+)
+
+// APIKeyHeader is the header carrying the api key
+const APIKeyHeader string = "X-API-Key"
+
+// APIKeyAuthMiddleware authenticates the requests carrying an api key
+// Requests without the header are left to the jwt authentication, rejected keys are recorded to the audit log.
+func APIKeyAuthMiddleware(service application.APIKeyQueryServiceInterface, auditor auditApplication.AuditEventCommandServiceInterface) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(APIKeyHeader)
+			if len(key) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			identity, err := service.AuthenticateAPIKey(r.Context(), serviceTypes.AuthenticateAPIKey{
+				Key: key,
+				IP:  requestinfo.FromContext(r.Context()).IP,
+			})
+			if err != nil {
+				var httpCode int
+				var errorMsg string
+				var errorCode string
+
+				switch err.Error() {
+				case errors.UnauthorizedAccess:
+					httpCode = http.StatusUnauthorized
+					errorMsg = "Invalid API key."
+					errorCode = errors.UnauthorizedAccess
+				case errors.ForbiddenAccess:
+					httpCode = http.StatusForbidden
+					errorMsg = "API key is not allowed from this address."
+					errorCode = errors.ForbiddenAccess
+				default:
+					httpCode = http.StatusInternalServerError
+					errorMsg = "Error while verifying API key."
+					errorCode = err.Error()
+				}
+
+				response := viewmodels.HTTPResponseVM{
+					Status:    httpCode,
+					Success:   false,
+					Message:   errorMsg,
+					ErrorCode: errorCode,
+				}
+
+				auditAuthFailure(auditor, r, errorCode)
+				response.JSON(w)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), identity)))
+		})
+	}
+}
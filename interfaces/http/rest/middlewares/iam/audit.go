@@ -0,0 +1,19 @@
+package jwt
+
+import (
+	"net/http"
+
+	auditApplication "gomora/module/audit/application"
+	"gomora/module/audit/domain/entity"
+	auditTypes "gomora/module/audit/infrastructure/service/types"
+)
+
+// auditAuthFailure records the rejected authentication of the request
+func auditAuthFailure(auditor auditApplication.AuditEventCommandServiceInterface, r *http.Request, errorCode string) {
+	_ = auditor.RecordAuditEvent(r.Context(), auditTypes.RecordAuditEvent{
+		Action:     entity.ActionAuthFailure,
+		ResourceID: r.URL.Path,
+		Outcome:    entity.OutcomeFailure,
+		ErrorCode:  errorCode,
+	})
+}
@@ -6,57 +6,102 @@ import (
 	"github.com/go-chi/jwtauth/v5"
 
 	"gomora/interfaces/http/rest/viewmodels"
+	"gomora/internal/auth"
 	"gomora/internal/errors"
+	auditApplication "gomora/module/audit/application"
 )
 
 // JWTAuthMiddleware handles JWT authentication custom errors
-func JWTAuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		_, claims, err := jwtauth.FromContext(r.Context())
-		if err != nil {
-			var httpCode int
-			var errorMsg string
-
-			switch err {
-			case jwtauth.ErrExpired:
-				httpCode = http.StatusUnauthorized
-				errorMsg = "Token has expired."
-			case jwtauth.ErrNoTokenFound:
-				httpCode = http.StatusUnauthorized
-				errorMsg = "No token found."
-			case jwtauth.ErrUnauthorized:
-				httpCode = http.StatusUnauthorized
-				errorMsg = "Invalid token."
-			default:
-				httpCode = http.StatusUnauthorized
-				errorMsg = "Invalid token"
+// Rejected authentications are recorded to the audit log.
+func JWTAuthMiddleware(auditor auditApplication.AuditEventCommandServiceInterface) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// already authenticated by an api key
+			if _, ok := auth.FromContext(r.Context()); ok {
+				next.ServeHTTP(w, r)
+				return
 			}
 
-			response := viewmodels.HTTPResponseVM{
-				Status:    httpCode,
-				Success:   false,
-				Message:   errorMsg,
-				ErrorCode: errors.UnauthorizedAccess,
+			_, claims, err := jwtauth.FromContext(r.Context())
+			if err != nil {
+				var httpCode int
+				var errorMsg string
+
+				switch err {
+				case jwtauth.ErrExpired:
+					httpCode = http.StatusUnauthorized
+					errorMsg = "Token has expired."
+				case jwtauth.ErrNoTokenFound:
+					httpCode = http.StatusUnauthorized
+					errorMsg = "No token found."
+				case jwtauth.ErrUnauthorized:
+					httpCode = http.StatusUnauthorized
+					errorMsg = "Invalid token."
+				default:
+					httpCode = http.StatusUnauthorized
+					errorMsg = "Invalid token"
+				}
+
+				response := viewmodels.HTTPResponseVM{
+					Status:    httpCode,
+					Success:   false,
+					Message:   errorMsg,
+					ErrorCode: errors.UnauthorizedAccess,
+				}
+
+				auditAuthFailure(auditor, r, errors.UnauthorizedAccess)
+				response.JSON(w)
+				return
 			}
 
-			response.JSON(w)
-			return
-		}
-
-		// if token is nil, creates unauthorized response
-		if claims == nil {
-			response := viewmodels.HTTPResponseVM{
-				Status:    http.StatusUnauthorized,
-				Success:   false,
-				Message:   "Invalid token",
-				ErrorCode: errors.UnauthorizedAccess,
+			// if token is nil, creates unauthorized response
+			if claims == nil {
+				response := viewmodels.HTTPResponseVM{
+					Status:    http.StatusUnauthorized,
+					Success:   false,
+					Message:   "Invalid token",
+					ErrorCode: errors.UnauthorizedAccess,
+				}
+
+				auditAuthFailure(auditor, r, errors.UnauthorizedAccess)
+				response.JSON(w)
+				return
 			}
 
-			response.JSON(w)
-			return
-		}
+			// tokens without a subject cannot own records
+			identity := auth.IdentityFromClaims(claims)
+			if len(identity.Subject) == 0 {
+				response := viewmodels.HTTPResponseVM{
+					Status:    http.StatusUnauthorized,
+					Success:   false,
+					Message:   "Invalid token",
+					ErrorCode: errors.UnauthorizedAccess,
+				}
+
+				auditAuthFailure(auditor, r, errors.UnauthorizedAccess)
+				response.JSON(w)
+				return
+			}
+
+			// if token is valid, proceeds to the next handler with the caller identity
+			next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), identity)))
+		})
+	}
+}
+
+// OptionalJWTAuthMiddleware authenticates the requests carrying a token like JWTAuthMiddleware
+// Requests without a token proceed anonymously, invalid tokens are still rejected.
+func OptionalJWTAuthMiddleware(auditor auditApplication.AuditEventCommandServiceInterface) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		authenticated := JWTAuthMiddleware(auditor)(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, _, err := jwtauth.FromContext(r.Context()); err == jwtauth.ErrNoTokenFound {
+				next.ServeHTTP(w, r)
+				return
+			}
 
-		// if token is valid, proceeds to the next handler
-		next.ServeHTTP(w, r)
-	})
+			authenticated.ServeHTTP(w, r)
+		})
+	}
 }
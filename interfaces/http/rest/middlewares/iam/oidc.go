@@ -0,0 +1,71 @@
+package jwt
+
+import (
+	"net/http"
+
+	"github.com/go-chi/jwtauth/v5"
+
+	"gomora/infrastructures/oidc"
+	"gomora/infrastructures/oidc/types"
+	"gomora/interfaces/http/rest/viewmodels"
+	"gomora/internal/auth"
+	"gomora/internal/errors"
+	auditApplication "gomora/module/audit/application"
+)
+
+// OIDCAuthMiddleware authenticates the bearer tokens issued by the external OpenID Connect provider
+// Tokens from other issuers are left to the jwt authentication, a nil provider disables the middleware.
+// Rejected tokens are recorded to the audit log.
+func OIDCAuthMiddleware(provider types.OIDCProviderInterface, auditor auditApplication.AuditEventCommandServiceInterface) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if provider == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// already authenticated by an api key
+			if _, ok := auth.FromContext(r.Context()); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token := jwtauth.TokenFromHeader(r)
+			if !oidc.IssuedBy(token, provider.Issuer()) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			identity, err := provider.Verify(r.Context(), token)
+			if err != nil {
+				var httpCode int
+				var errorMsg string
+				var errorCode string
+
+				switch err {
+				case oidc.ErrInvalidToken:
+					httpCode = http.StatusUnauthorized
+					errorMsg = "Invalid token."
+					errorCode = errors.UnauthorizedAccess
+				default:
+					httpCode = http.StatusServiceUnavailable
+					errorMsg = "Error while verifying token."
+					errorCode = errors.ServerError
+				}
+
+				response := viewmodels.HTTPResponseVM{
+					Status:    httpCode,
+					Success:   false,
+					Message:   errorMsg,
+					ErrorCode: errorCode,
+				}
+
+				auditAuthFailure(auditor, r, errorCode)
+				response.JSON(w)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), identity)))
+		})
+	}
+}
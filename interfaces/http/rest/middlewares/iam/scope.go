@@ -0,0 +1,40 @@
+package jwt
+
+import (
+	"net/http"
+
+	"gomora/interfaces/http/rest/viewmodels"
+	"gomora/internal/auth"
+	"gomora/internal/errors"
+	auditApplication "gomora/module/audit/application"
+)
+
+// RequireScope allows only the authenticated callers granted with one of the scopes
+// It must run after the authentication middlewares, denied requests are recorded to the audit log.
+func RequireScope(auditor auditApplication.AuditEventCommandServiceInterface, scopes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity, ok := auth.FromContext(r.Context())
+
+			granted := false
+			for _, scope := range scopes {
+				granted = granted || identity.HasScope(scope)
+			}
+
+			if !ok || !granted {
+				response := viewmodels.HTTPResponseVM{
+					Status:    http.StatusForbidden,
+					Success:   false,
+					Message:   "Forbidden access.",
+					ErrorCode: errors.ForbiddenAccess,
+				}
+
+				auditAuthFailure(auditor, r, errors.ForbiddenAccess)
+				response.JSON(w)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
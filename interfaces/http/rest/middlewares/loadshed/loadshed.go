@@ -0,0 +1,77 @@
+package loadshed
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"gomora/infrastructures/loadshed"
+	"gomora/interfaces/http/rest/viewmodels"
+	"gomora/internal/errors"
+)
+
+// LoadShedMiddleware sheds the requests beyond the concurrency limit with a 503, the writes first
+// The health check is never shed. The exempt paths, like long-lived streams, bypass the limiter.
+func LoadShedMiddleware(limiter *loadshed.Limiter, exempt ...string) func(http.Handler) http.Handler {
+	exemptPaths := map[string]bool{}
+	for _, path := range exempt {
+		exemptPaths[path] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		if limiter == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if exemptPaths[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			release, ok := limiter.Acquire(priority(r))
+			if !ok {
+				response := viewmodels.HTTPResponseVM{
+					Status:    http.StatusServiceUnavailable,
+					Success:   false,
+					Message:   "Service is overloaded, please try again later.",
+					ErrorCode: errors.ServerOverloaded,
+				}
+
+				w.Header().Set("Retry-After", "1")
+				response.JSON(w)
+				return
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			failed := true
+			defer func() {
+				release(failed)
+			}()
+
+			next.ServeHTTP(ww, r)
+			failed = overloaded(ww.Status())
+		})
+	}
+}
+
+// overloaded returns true for the statuses of a failing server
+// The rejections like maintenance, the open circuits or the timeouts are not failures, the slow requests are
+// already counted by their latency.
+func overloaded(status int) bool {
+	return status == http.StatusInternalServerError || status == http.StatusBadGateway
+}
+
+// priority returns the priority of the request: the health check, then the reads, then the writes
+func priority(r *http.Request) loadshed.Priority {
+	if r.URL.Path == "/" {
+		return loadshed.PriorityCritical
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return loadshed.PriorityRead
+	default:
+		return loadshed.PriorityWrite
+	}
+}
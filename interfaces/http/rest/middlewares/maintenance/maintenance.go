@@ -0,0 +1,60 @@
+package maintenance
+
+import (
+	"net/http"
+
+	"gomora/infrastructures/maintenance"
+	"gomora/interfaces/http/rest/viewmodels"
+	"gomora/internal/errors"
+)
+
+// MaintenanceMiddleware rejects the requests during maintenance with a 503: all of them in full maintenance,
+// the writes in read-only maintenance. The reads, keyed by method and path like "POST /v1/record/token/generate",
+// are the requests without side effects despite their method. The exempt paths, like the maintenance endpoint,
+// are always served.
+func MaintenanceMiddleware(reads map[string]bool, exempt ...string) func(http.Handler) http.Handler {
+	exemptPaths := map[string]bool{}
+	for _, path := range exempt {
+		exemptPaths[path] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			status := maintenance.Current()
+			if !status.BlocksWrites() || exemptPaths[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var errorMsg string
+			switch {
+			case status.BlocksReads():
+				errorMsg = "Service is under maintenance, please try again later."
+			case isWrite(r) && !reads[r.Method+" "+r.URL.Path]:
+				errorMsg = "Service is read-only during maintenance, please try again later."
+			default:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			response := viewmodels.HTTPResponseVM{
+				Status:    http.StatusServiceUnavailable,
+				Success:   false,
+				Message:   errorMsg,
+				ErrorCode: errors.ServerMaintenance,
+			}
+
+			response.JSON(w)
+		})
+	}
+}
+
+// isWrite returns true for the requests with side effects
+func isWrite(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+
+	"gomora/infrastructures/ratelimit"
+	"gomora/interfaces/http/rest/viewmodels"
+	"gomora/internal/errors"
+)
+
+// RateLimitMiddleware limits the requests of every client on the route, with the RateLimit-* quota headers
+// It must run after the authentication middlewares to limit the callers by api key or subject rather than by ip.
+func RateLimitMiddleware(limiter *ratelimit.Limiter, route string) func(http.Handler) http.Handler {
+	return rateLimit(limiter, route, ratelimit.ClientKey)
+}
+
+// IPRateLimitMiddleware limits the requests of every client ip on the route
+// It runs before the authentication middlewares, so that the floods of rejected credentials are turned away
+// before they are checked and audited.
+func IPRateLimitMiddleware(limiter *ratelimit.Limiter, route string) func(http.Handler) http.Handler {
+	return rateLimit(limiter, route, ratelimit.IPKey)
+}
+
+// rateLimit limits the requests of every client identified by the key on the route
+func rateLimit(limiter *ratelimit.Limiter, route string, clientKey func(ctx context.Context) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, limited := limiter.Take(r.Context(), route, clientKey(r.Context()))
+			if !limited {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			for key, value := range ratelimit.Headers(result) {
+				w.Header().Set(key, value)
+			}
+
+			if !result.Allowed {
+				response := viewmodels.HTTPResponseVM{
+					Status:    http.StatusTooManyRequests,
+					Success:   false,
+					Message:   "Too many requests, please try again later.",
+					ErrorCode: errors.MaximumLimitReached,
+				}
+
+				response.JSON(w)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package requestinfo
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"gomora/internal/requestinfo"
+)
+
+// RequestInfoMiddleware passes the request id and the client ip to the request context
+// The client ip is the address of the peer, or the one forwarded by the peer when it is a trusted proxy.
+// The remote address is replaced by the client ip, like the RealIP middleware does. It must run after the
+// RequestID middleware.
+func RequestInfoMiddleware(proxies requestinfo.TrustedProxies) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			peerIP, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				peerIP = r.RemoteAddr
+			}
+
+			ip := proxies.ClientIP(peerIP, r.Header.Values("X-Forwarded-For"), r.Header.Get("X-Real-IP"))
+			r.RemoteAddr = ip
+
+			ctx := requestinfo.NewContext(r.Context(), requestinfo.Info{
+				RequestID: middleware.GetReqID(r.Context()),
+				IP:        ip,
+			})
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package tenant
+
+import (
+	"net/http"
+
+	"gomora/interfaces/http/rest/viewmodels"
+	"gomora/internal/auth"
+	"gomora/internal/errors"
+	"gomora/internal/tenant"
+)
+
+// TenantMiddleware resolves the tenant of the request from the token claim, the default tenant without one
+// An X-Tenant-ID header naming another tenant is rejected. It must run after the authentication middleware.
+func TenantMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, _ := auth.FromContext(r.Context())
+
+		tenantID, err := tenant.Resolve(identity.TenantID, r.Header.Get(tenant.HeaderName))
+		if err != nil {
+			var httpCode int
+			var errorMsg string
+			var errorCode string
+
+			switch err {
+			case tenant.ErrInvalidTenant:
+				httpCode = http.StatusBadRequest
+				errorMsg = "Invalid tenant ID."
+				errorCode = errors.InvalidRequestPayload
+			default:
+				httpCode = http.StatusForbidden
+				errorMsg = "Tenant access is forbidden."
+				errorCode = errors.ForbiddenAccess
+			}
+
+			response := viewmodels.HTTPResponseVM{
+				Status:    httpCode,
+				Success:   false,
+				Message:   errorMsg,
+				ErrorCode: errorCode,
+			}
+
+			response.JSON(w)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(tenant.NewContext(r.Context(), tenantID)))
+	})
+}
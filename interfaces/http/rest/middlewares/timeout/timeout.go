@@ -0,0 +1,65 @@
+package timeout
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gomora/interfaces/http/rest/viewmodels"
+	"gomora/internal/errors"
+)
+
+// TimeoutMiddleware bounds the request with a deadline, the timeout of the route or the Request-Timeout header
+// The header is a duration like 500ms or decimal seconds, capped at the timeout of the route. No deadline is
+// set when neither is, a timeout of 0 lifts the timeout of the route.
+func TimeoutMiddleware(routeTimeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the timeout of this request only, the route timeout is shared by the concurrent requests
+			timeout := routeTimeout
+			if header := r.Header.Get("Request-Timeout"); len(header) > 0 {
+				requested, ok := parseTimeout(header)
+				if !ok {
+					response := viewmodels.HTTPResponseVM{
+						Status:    http.StatusBadRequest,
+						Success:   false,
+						Message:   "Invalid Request-Timeout header.",
+						ErrorCode: errors.InvalidRequestPayload,
+					}
+
+					response.JSON(w)
+					return
+				}
+
+				if timeout == 0 || requested < timeout {
+					timeout = requested
+				}
+			}
+
+			if timeout == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// parseTimeout parses a positive duration like 500ms or decimal seconds
+func parseTimeout(value string) (time.Duration, bool) {
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil || seconds > time.Duration(1<<63-1).Seconds() {
+			return 0, false
+		}
+		timeout = time.Duration(seconds * float64(time.Second))
+	}
+
+	return timeout, timeout > 0
+}
@@ -0,0 +1,83 @@
+package timeout
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	var deadline time.Time
+	var hasDeadline bool
+	handler := TimeoutMiddleware(time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, hasDeadline = r.Context().Deadline()
+	}))
+
+	tests := []struct {
+		name     string
+		header   string
+		expected time.Duration
+		status   int
+	}{
+		{name: "shorter requested timeout", header: "10ms", expected: time.Millisecond * 10, status: http.StatusOK},
+		// a request following one with a shorter timeout gets the timeout of the route back
+		{name: "route timeout", expected: time.Hour, status: http.StatusOK},
+		{name: "longer requested timeout capped", header: "7200", expected: time.Hour, status: http.StatusOK},
+		{name: "decimal seconds", header: "0.5", expected: time.Millisecond * 500, status: http.StatusOK},
+		{name: "invalid header", header: "soon", status: http.StatusBadRequest},
+		{name: "negative header", header: "-1s", status: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hasDeadline = false
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if len(test.header) > 0 {
+				r.Header.Set("Request-Timeout", test.header)
+			}
+			w := httptest.NewRecorder()
+			start := time.Now()
+			handler.ServeHTTP(w, r)
+			end := time.Now()
+
+			if w.Code != test.status {
+				t.Fatalf("expected %d, got %d", test.status, w.Code)
+			}
+			if test.status != http.StatusOK {
+				if hasDeadline {
+					t.Error("expected the request to be rejected")
+				}
+				return
+			}
+
+			if !hasDeadline {
+				t.Fatal("expected a deadline")
+			}
+			if deadline.Before(start.Add(test.expected)) || deadline.After(end.Add(test.expected)) {
+				t.Errorf("expected a timeout of %s, got %s", test.expected, deadline.Sub(start))
+			}
+		})
+	}
+}
+
+func TestTimeoutMiddlewareUnbounded(t *testing.T) {
+	var hasDeadline bool
+	handler := TimeoutMiddleware(0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, hasDeadline = r.Context().Deadline()
+	}))
+
+	for _, header := range []string{"10ms", ""} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if len(header) > 0 {
+			r.Header.Set("Request-Timeout", header)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		// only the requests asking for a timeout get one, the previous requests don't leak theirs
+		if hasDeadline != (len(header) > 0) {
+			t.Errorf("expected a deadline %t with %q, got %t", len(header) > 0, header, hasDeadline)
+		}
+	}
+}
@@ -10,6 +10,8 @@
 package rest
 
 import (
+	"context"
+	"expvar"
 	"fmt"
 	"log"
 	"net/http"
@@ -18,14 +20,24 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/afex/hystrix-go/hystrix"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/jwtauth/v5"
 
+	ratelimit_config "gomora/configs/ratelimit"
+	timeout_config "gomora/configs/timeout"
 	"gomora/interfaces"
 	"gomora/interfaces/http/rest/middlewares/cors"
 	jwt "gomora/interfaces/http/rest/middlewares/iam"
+	"gomora/interfaces/http/rest/middlewares/loadshed"
+	"gomora/interfaces/http/rest/middlewares/maintenance"
+	"gomora/interfaces/http/rest/middlewares/ratelimit"
+	"gomora/interfaces/http/rest/middlewares/requestinfo"
+	"gomora/interfaces/http/rest/middlewares/tenant"
+	"gomora/interfaces/http/rest/middlewares/timeout"
 	"gomora/interfaces/http/rest/viewmodels"
+	"gomora/internal/auth"
 )
 
 // ChiRouterInterface declares methods for the chi router
@@ -39,6 +51,8 @@ type router struct{}
 var (
 	m          *router
 	routerOnce sync.Once
+
+	hystrixStreamHandler *hystrix.StreamHandler
 )
 
 // InitRouter initializes main routes
@@ -46,14 +60,33 @@ func (router *router) InitRouter() *chi.Mux {
 	// DI assignment
 	recordCommandController := interfaces.ServiceContainer().RegisterRecordRESTCommandController()
 	recordQueryController := interfaces.ServiceContainer().RegisterRecordRESTQueryController()
+	apiKeyCommandController := interfaces.ServiceContainer().RegisterAPIKeyRESTCommandController()
+	apiKeyQueryController := interfaces.ServiceContainer().RegisterAPIKeyRESTQueryController()
+	apiKeyAuthenticator := interfaces.ServiceContainer().RegisterAPIKeyAuthenticator()
+	oidcProvider := interfaces.ServiceContainer().RegisterOIDCProvider()
+	auditEventQueryController := interfaces.ServiceContainer().RegisterAuditEventRESTQueryController()
+	auditor := interfaces.ServiceContainer().RegisterAuditor()
+	circuitCommandController := interfaces.ServiceContainer().RegisterCircuitRESTCommandController()
+	circuitQueryController := interfaces.ServiceContainer().RegisterCircuitRESTQueryController()
+	rateLimiter := interfaces.ServiceContainer().RegisterRateLimiter()
+	loadShedder := interfaces.ServiceContainer().RegisterLoadShedder()
+	maintenanceCommandController := interfaces.ServiceContainer().RegisterMaintenanceRESTCommandController()
+	maintenanceQueryController := interfaces.ServiceContainer().RegisterMaintenanceRESTQueryController()
+	requestTimeouts := interfaces.ServiceContainer().RegisterRequestTimeouts()
+
+	// the requests still served in read-only maintenance despite their method, the tokens write no data
+	maintenanceReads := map[string]bool{
+		"POST /v1/record/token/generate": true,
+	}
 
 	// create router
 	r := chi.NewRouter()
 
 	// global and recommended middlewares
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(requestinfo.RequestInfoMiddleware(interfaces.ServiceContainer().RegisterTrustedProxies()))
 	r.Use(middleware.Logger)
+	r.Use(loadshed.LoadShedMiddleware(loadShedder, "/v1/debug/hystrix.stream", "/v1/audit/events/export"))
 	r.Use(cors.Init().Handler)
 	r.Use(middleware.Recoverer)
 
@@ -82,19 +115,114 @@ func (router *router) InitRouter() *chi.Mux {
 
 	// API routes
 	r.Group(func(r chi.Router) {
+		r.Use(ratelimit.IPRateLimitMiddleware(rateLimiter, ratelimit_config.IP))
+		r.Use(maintenance.MaintenanceMiddleware(maintenanceReads, "/v1/maintenance", "/v1/maintenance/"))
+
 		r.Route("/v1", func(r chi.Router) {
 			tokenAuth := jwtauth.New("HS256", []byte(os.Getenv("JWT_SECRET")), nil)
 
+			// api key module
+			r.Route("/apikey", func(r chi.Router) {
+				r.Use(jwtauth.Verifier(tokenAuth))
+				r.Use(jwt.APIKeyAuthMiddleware(apiKeyAuthenticator, auditor))
+				r.Use(jwt.OIDCAuthMiddleware(oidcProvider, auditor))
+				r.Use(jwt.JWTAuthMiddleware(auditor))
+				r.Use(ratelimit.RateLimitMiddleware(rateLimiter, ratelimit_config.Admin))
+				r.Use(jwt.RequireScope(auditor, auth.ScopeAdmin))
+				r.Use(timeout.TimeoutMiddleware(requestTimeouts[timeout_config.Admin]))
+
+				r.Post("/", apiKeyCommandController.CreateAPIKey)
+				r.Get("/", apiKeyQueryController.GetAPIKeys)
+				r.Get("/{id}", apiKeyQueryController.GetAPIKeyByID)
+				r.Delete("/{id}", apiKeyCommandController.RevokeAPIKey)
+			})
+
+			// audit module
+			r.Route("/audit", func(r chi.Router) {
+				r.Use(jwtauth.Verifier(tokenAuth))
+				r.Use(jwt.APIKeyAuthMiddleware(apiKeyAuthenticator, auditor))
+				r.Use(jwt.OIDCAuthMiddleware(oidcProvider, auditor))
+				r.Use(jwt.JWTAuthMiddleware(auditor))
+				r.Use(ratelimit.RateLimitMiddleware(rateLimiter, ratelimit_config.Admin))
+				r.Use(jwt.RequireScope(auditor, auth.ScopeAdmin, auth.ScopeAudit))
+
+				r.With(timeout.TimeoutMiddleware(requestTimeouts[timeout_config.Admin])).Get("/events", auditEventQueryController.GetAuditEvents)
+				// the export streams for as long as it takes
+				r.Get("/events/export", auditEventQueryController.ExportAuditEvents)
+			})
+
+			// circuit breakers
+			r.Route("/circuits", func(r chi.Router) {
+				r.Use(jwtauth.Verifier(tokenAuth))
+				r.Use(jwt.APIKeyAuthMiddleware(apiKeyAuthenticator, auditor))
+				r.Use(jwt.OIDCAuthMiddleware(oidcProvider, auditor))
+				r.Use(jwt.JWTAuthMiddleware(auditor))
+				r.Use(ratelimit.RateLimitMiddleware(rateLimiter, ratelimit_config.Admin))
+				r.Use(jwt.RequireScope(auditor, auth.ScopeAdmin))
+				r.Use(timeout.TimeoutMiddleware(requestTimeouts[timeout_config.Admin]))
+
+				r.Get("/", circuitQueryController.GetCircuits)
+				r.Post("/{name}/open", circuitCommandController.ForceOpenCircuit)
+				r.Post("/{name}/reset", circuitCommandController.ResetCircuit)
+			})
+
+			// maintenance mode, served during maintenance to switch it off
+			r.Route("/maintenance", func(r chi.Router) {
+				r.Use(jwtauth.Verifier(tokenAuth))
+				r.Use(jwt.APIKeyAuthMiddleware(apiKeyAuthenticator, auditor))
+				r.Use(jwt.OIDCAuthMiddleware(oidcProvider, auditor))
+				r.Use(jwt.JWTAuthMiddleware(auditor))
+				r.Use(ratelimit.RateLimitMiddleware(rateLimiter, ratelimit_config.Admin))
+				r.Use(jwt.RequireScope(auditor, auth.ScopeAdmin))
+				r.Use(timeout.TimeoutMiddleware(requestTimeouts[timeout_config.Admin]))
+
+				r.Get("/", maintenanceQueryController.GetMaintenance)
+				r.Put("/", maintenanceCommandController.SetMaintenance)
+			})
+
+			// metrics, like the database connection pool statistics and the circuit breakers event stream
+			r.Route("/debug", func(r chi.Router) {
+				r.Use(jwtauth.Verifier(tokenAuth))
+				r.Use(jwt.APIKeyAuthMiddleware(apiKeyAuthenticator, auditor))
+				r.Use(jwt.OIDCAuthMiddleware(oidcProvider, auditor))
+				r.Use(jwt.JWTAuthMiddleware(auditor))
+				r.Use(ratelimit.RateLimitMiddleware(rateLimiter, ratelimit_config.Admin))
+				r.Use(jwt.RequireScope(auditor, auth.ScopeAdmin))
+
+				r.Get("/vars", expvar.Handler().ServeHTTP)
+				r.Get("/hystrix.stream", HystrixStream)
+			})
+
 			// record module
 			r.Route("/record", func(r chi.Router) {
-				r.Post("/token/generate", recordCommandController.GenerateToken)
+				// anonymous callers get a token of their own, admins can choose its subject
+				r.Group(func(r chi.Router) {
+					r.Use(jwtauth.Verifier(tokenAuth))
+					r.Use(jwt.APIKeyAuthMiddleware(apiKeyAuthenticator, auditor))
+					r.Use(jwt.OIDCAuthMiddleware(oidcProvider, auditor))
+					r.Use(jwt.OptionalJWTAuthMiddleware(auditor))
+
+					r.With(
+						ratelimit.RateLimitMiddleware(rateLimiter, ratelimit_config.GenerateToken),
+						timeout.TimeoutMiddleware(requestTimeouts[timeout_config.GenerateToken]),
+					).Post("/token/generate", recordCommandController.GenerateToken)
+				})
 
 				r.Group(func(r chi.Router) {
 					r.Use(jwtauth.Verifier(tokenAuth))
-					r.Use(jwt.JWTAuthMiddleware)
+					r.Use(jwt.APIKeyAuthMiddleware(apiKeyAuthenticator, auditor))
+					r.Use(jwt.OIDCAuthMiddleware(oidcProvider, auditor))
+					r.Use(jwt.JWTAuthMiddleware(auditor))
+					r.Use(tenant.TenantMiddleware)
 
-					r.Post("/", recordCommandController.CreateRecord)
-					r.Get("/{id}", recordQueryController.GetRecordByID)
+					r.With(
+						ratelimit.RateLimitMiddleware(rateLimiter, ratelimit_config.CreateRecord),
+						timeout.TimeoutMiddleware(requestTimeouts[timeout_config.CreateRecord]),
+					).Post("/", recordCommandController.CreateRecord)
+					r.With(
+						ratelimit.RateLimitMiddleware(rateLimiter, ratelimit_config.GetRecord),
+						timeout.TimeoutMiddleware(requestTimeouts[timeout_config.GetRecord]),
+					).Get("/{id}", recordQueryController.GetRecordByID)
 				})
 			})
 		})
@@ -124,6 +252,35 @@ func FileServer(r chi.Router, path string, root http.FileSystem) {
 	})
 }
 
+// HystrixStream serves the hystrix metrics event stream
+// The stream handler expects an http.CloseNotifier, which the writers wrapped by the middlewares don't implement.
+func HystrixStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	hystrixStreamHandler.ServeHTTP(&closeNotifier{w, flusher, r.Context()}, r)
+}
+
+// closeNotifier notifies the close of the connection from the request context
+type closeNotifier struct {
+	http.ResponseWriter
+	http.Flusher
+	ctx context.Context
+}
+
+func (w *closeNotifier) CloseNotify() <-chan bool {
+	closed := make(chan bool, 1)
+	go func() {
+		<-w.ctx.Done()
+		closed <- true
+	}()
+
+	return closed
+}
+
 func (router *router) Serve(port int) {
 	log.Printf("[SERVER] REST server running on :%d", port)
 	err := http.ListenAndServe(fmt.Sprintf(":%d", port), router.InitRouter())
@@ -132,7 +289,11 @@ func (router *router) Serve(port int) {
 	}
 }
 
-func registerHandlers() {}
+func registerHandlers() {
+	// publishes the circuit breaker metrics to the dashboards connected to the stream
+	hystrixStreamHandler = hystrix.NewStreamHandler()
+	hystrixStreamHandler.Start()
+}
 
 // ChiRouter export instantiated chi router once
 func ChiRouter() ChiRouterInterface {
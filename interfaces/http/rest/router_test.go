@@ -0,0 +1,44 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	maintenanceMode "gomora/infrastructures/maintenance"
+)
+
+func TestRouterMaintenance(t *testing.T) {
+	t.Setenv("STORAGE_BACKEND", "memory")
+	t.Setenv("JWT_SECRET", "secret")
+
+	router := ChiRouter().InitRouter()
+	serve := func(method, path string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader("{}")))
+
+		return w.Code
+	}
+
+	if _, err := maintenanceMode.Set(maintenanceMode.ModeReadOnly, "test"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _, _ = maintenanceMode.Set(maintenanceMode.ModeOff, "") })
+
+	// the tokens write no data, the clients still authenticate in read-only maintenance
+	if code := serve(http.MethodPost, "/v1/record/token/generate"); code != http.StatusOK {
+		t.Errorf("expected the token to be generated, got %d", code)
+	}
+	if code := serve(http.MethodPost, "/v1/record"); code != http.StatusServiceUnavailable {
+		t.Errorf("expected the write to be rejected, got %d", code)
+	}
+
+	// but not in full maintenance
+	if _, err := maintenanceMode.Set(maintenanceMode.ModeFull, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if code := serve(http.MethodPost, "/v1/record/token/generate"); code != http.StatusServiceUnavailable {
+		t.Errorf("expected the token to be rejected, got %d", code)
+	}
+}
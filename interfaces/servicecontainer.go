@@ -10,12 +10,47 @@
 package interfaces
 
 import (
+	"context"
+	"errors"
+	"expvar"
 	"log"
 	"os"
+	"strconv"
 	"sync"
-
-	"gomora/infrastructures/database/mysql"
-	"gomora/infrastructures/database/mysql/types"
+	"time"
+
+	hystrix_config "gomora/configs/hystrix"
+	ratelimit_config "gomora/configs/ratelimit"
+	timeout_config "gomora/configs/timeout"
+	"gomora/infrastructures/cache"
+	cacheTypes "gomora/infrastructures/cache/types"
+	"gomora/infrastructures/database"
+	dbTypes "gomora/infrastructures/database/types"
+	"gomora/infrastructures/loadshed"
+	loadshedTypes "gomora/infrastructures/loadshed/types"
+	"gomora/infrastructures/maintenance"
+	"gomora/infrastructures/oidc"
+	oidcTypes "gomora/infrastructures/oidc/types"
+	"gomora/infrastructures/ratelimit"
+	ratelimitTypes "gomora/infrastructures/ratelimit/types"
+	"gomora/infrastructures/redis"
+	redisTypes "gomora/infrastructures/redis/types"
+	"gomora/internal/requestinfo"
+	apiKeyApplication "gomora/module/apikey/application"
+	apiKeyDomainRepository "gomora/module/apikey/domain/repository"
+	apiKeyRepository "gomora/module/apikey/infrastructure/repository"
+	apiKeyService "gomora/module/apikey/infrastructure/service"
+	apiKeyREST "gomora/module/apikey/interfaces/http/rest"
+	auditApplication "gomora/module/audit/application"
+	auditDomainRepository "gomora/module/audit/domain/repository"
+	auditRepository "gomora/module/audit/infrastructure/repository"
+	auditService "gomora/module/audit/infrastructure/service"
+	auditREST "gomora/module/audit/interfaces/http/rest"
+	circuitService "gomora/module/circuit/infrastructure/service"
+	circuitREST "gomora/module/circuit/interfaces/http/rest"
+	maintenanceService "gomora/module/maintenance/infrastructure/service"
+	maintenanceREST "gomora/module/maintenance/interfaces/http/rest"
+	recordDomainRepository "gomora/module/record/domain/repository"
 	recordRepository "gomora/module/record/infrastructure/repository"
 	recordService "gomora/module/record/infrastructure/service"
 	recordGRPC "gomora/module/record/interfaces/http/grpc"
@@ -29,17 +64,50 @@ type ServiceContainerInterface interface {
 	RegisterRecordGRPCQueryController() recordGRPC.RecordQueryController
 
 	// REST
+	RegisterAPIKeyRESTCommandController() apiKeyREST.APIKeyCommandController
+	RegisterAPIKeyRESTQueryController() apiKeyREST.APIKeyQueryController
+	RegisterAuditEventRESTQueryController() auditREST.AuditEventQueryController
+	RegisterCircuitRESTCommandController() circuitREST.CircuitCommandController
+	RegisterCircuitRESTQueryController() circuitREST.CircuitQueryController
+	RegisterMaintenanceRESTCommandController() maintenanceREST.MaintenanceCommandController
+	RegisterMaintenanceRESTQueryController() maintenanceREST.MaintenanceQueryController
 	RegisterRecordRESTCommandController() recordREST.RecordCommandController
 	RegisterRecordRESTQueryController() recordREST.RecordQueryController
+
+	// Middlewares
+	RegisterAPIKeyAuthenticator() apiKeyApplication.APIKeyQueryServiceInterface
+	RegisterOIDCProvider() oidcTypes.OIDCProviderInterface
+	RegisterAuditor() auditApplication.AuditEventCommandServiceInterface
+	RegisterRateLimiter() *ratelimit.Limiter
+	RegisterLoadShedder() *loadshed.Limiter
+	RegisterRequestTimeouts() map[string]time.Duration
+	RegisterTrustedProxies() requestinfo.TrustedProxies
 }
 
 type kernel struct{}
 
 var (
-	m              sync.Mutex
-	k              *kernel
-	containerOnce  sync.Once
-	mysqlDBHandler *mysql.MySQLDBHandler
+	m             sync.Mutex
+	k             *kernel
+	containerOnce sync.Once
+	dbHandler     dbTypes.DBHandlerInterface // nil when STORAGE_BACKEND=memory
+	oidcProvider  *oidc.OIDCProvider
+	rateLimiter   *ratelimit.Limiter
+	loadShedder   *loadshed.Limiter // set when LOAD_SHED_ENABLED is
+
+	requestTimeouts map[string]time.Duration
+	trustedProxies  requestinfo.TrustedProxies
+
+	// limits the rejected authentications recorded to the audit log
+	auditAuthFailureStore = ratelimit.NewMemoryStore()
+
+	transactionHandler dbTypes.TransactionHandlerInterface
+
+	apiKeyMemoryRepository     *apiKeyRepository.APIKeyMemoryRepository    // set when STORAGE_BACKEND=memory
+	auditEventMemoryRepository *auditRepository.AuditEventMemoryRepository // set when STORAGE_BACKEND=memory
+	recordMemoryRepository     *recordRepository.RecordMemoryRepository    // set when STORAGE_BACKEND=memory
+	recordStaleCache           *recordRepository.RecordStaleCache          // set when RECORD_STALE_TTL is
+	recordCache                cacheTypes.CacheBackendInterface            // set when RECORD_CACHE is
 )
 
 // ================================= gRPC ===================================
@@ -68,6 +136,28 @@ func (k *kernel) RegisterRecordGRPCQueryController() recordGRPC.RecordQueryContr
 //==========================================================================
 
 // ================================= REST ===================================
+// RegisterAPIKeyRESTCommandController performs dependency injection to the RegisterAPIKeyRESTCommandController
+func (k *kernel) RegisterAPIKeyRESTCommandController() apiKeyREST.APIKeyCommandController {
+	service := k.apiKeyCommandServiceContainer()
+
+	controller := apiKeyREST.APIKeyCommandController{
+		APIKeyCommandServiceInterface: service,
+	}
+
+	return controller
+}
+
+// RegisterAPIKeyRESTQueryController performs dependency injection to the RegisterAPIKeyRESTQueryController
+func (k *kernel) RegisterAPIKeyRESTQueryController() apiKeyREST.APIKeyQueryController {
+	service := k.apiKeyQueryServiceContainer()
+
+	controller := apiKeyREST.APIKeyQueryController{
+		APIKeyQueryServiceInterface: service,
+	}
+
+	return controller
+}
+
 // RegisterRecordRESTCommandController performs dependency injection to the RegisterRecordRESTCommandController
 func (k *kernel) RegisterRecordRESTCommandController() recordREST.RecordCommandController {
 	service := k.recordCommandServiceContainer()
@@ -90,31 +180,216 @@ func (k *kernel) RegisterRecordRESTQueryController() recordREST.RecordQueryContr
 	return controller
 }
 
+// RegisterAuditEventRESTQueryController performs dependency injection to the RegisterAuditEventRESTQueryController
+func (k *kernel) RegisterAuditEventRESTQueryController() auditREST.AuditEventQueryController {
+	service := k.auditEventQueryServiceContainer()
+
+	controller := auditREST.AuditEventQueryController{
+		AuditEventQueryServiceInterface: service,
+	}
+
+	return controller
+}
+
+// RegisterCircuitRESTCommandController performs dependency injection to the RegisterCircuitRESTCommandController
+func (k *kernel) RegisterCircuitRESTCommandController() circuitREST.CircuitCommandController {
+	service := &circuitService.CircuitCommandService{
+		AuditEventCommandServiceInterface: k.auditEventCommandServiceContainer(),
+	}
+
+	controller := circuitREST.CircuitCommandController{
+		CircuitCommandServiceInterface: service,
+	}
+
+	return controller
+}
+
+// RegisterCircuitRESTQueryController performs dependency injection to the RegisterCircuitRESTQueryController
+func (k *kernel) RegisterCircuitRESTQueryController() circuitREST.CircuitQueryController {
+	controller := circuitREST.CircuitQueryController{
+		CircuitQueryServiceInterface: &circuitService.CircuitQueryService{},
+	}
+
+	return controller
+}
+
+// RegisterMaintenanceRESTCommandController performs dependency injection to the RegisterMaintenanceRESTCommandController
+func (k *kernel) RegisterMaintenanceRESTCommandController() maintenanceREST.MaintenanceCommandController {
+	service := &maintenanceService.MaintenanceCommandService{
+		AuditEventCommandServiceInterface: k.auditEventCommandServiceContainer(),
+	}
+
+	controller := maintenanceREST.MaintenanceCommandController{
+		MaintenanceCommandServiceInterface: service,
+	}
+
+	return controller
+}
+
+// RegisterMaintenanceRESTQueryController performs dependency injection to the RegisterMaintenanceRESTQueryController
+func (k *kernel) RegisterMaintenanceRESTQueryController() maintenanceREST.MaintenanceQueryController {
+	controller := maintenanceREST.MaintenanceQueryController{
+		MaintenanceQueryServiceInterface: &maintenanceService.MaintenanceQueryService{},
+	}
+
+	return controller
+}
+
+//==========================================================================
+
+// ============================== Middlewares ===============================
+// RegisterAPIKeyAuthenticator performs dependency injection to the api key authentication middlewares
+func (k *kernel) RegisterAPIKeyAuthenticator() apiKeyApplication.APIKeyQueryServiceInterface {
+	return k.apiKeyQueryServiceContainer()
+}
+
+// RegisterOIDCProvider performs dependency injection to the OpenID Connect authentication middlewares
+// It returns nil when no external provider is configured.
+func (k *kernel) RegisterOIDCProvider() oidcTypes.OIDCProviderInterface {
+	if oidcProvider == nil {
+		return nil
+	}
+
+	return oidcProvider
+}
+
+// RegisterAuditor performs dependency injection to the audit logging of the authentication middlewares
+func (k *kernel) RegisterAuditor() auditApplication.AuditEventCommandServiceInterface {
+	return k.auditEventCommandServiceContainer()
+}
+
+// RegisterRateLimiter returns the rate limiter shared by the REST middlewares and the gRPC interceptors
+func (k *kernel) RegisterRateLimiter() *ratelimit.Limiter {
+	return rateLimiter
+}
+
+// RegisterLoadShedder returns the concurrency limiter shared by the REST and gRPC servers, nil when disabled
+func (k *kernel) RegisterLoadShedder() *loadshed.Limiter {
+	return loadShedder
+}
+
+// RegisterRequestTimeouts returns the timeouts of the routes shared by the REST and gRPC servers, keyed by route name
+func (k *kernel) RegisterRequestTimeouts() map[string]time.Duration {
+	return requestTimeouts
+}
+
+// RegisterTrustedProxies returns the reverse proxies allowed to forward the client ip to the REST server
+func (k *kernel) RegisterTrustedProxies() requestinfo.TrustedProxies {
+	return trustedProxies
+}
+
 //==========================================================================
 
+func (k *kernel) apiKeyCommandServiceContainer() *apiKeyService.APIKeyCommandService {
+	var repository apiKeyDomainRepository.APIKeyCommandRepositoryInterface = &apiKeyRepository.APIKeyCommandRepository{
+		DBHandlerInterface: dbHandler,
+	}
+	if apiKeyMemoryRepository != nil {
+		repository = apiKeyMemoryRepository
+	}
+
+	service := &apiKeyService.APIKeyCommandService{
+		APIKeyCommandRepositoryInterface:  repository,
+		AuditEventCommandServiceInterface: k.auditEventCommandServiceContainer(),
+	}
+
+	return service
+}
+
+func (k *kernel) apiKeyQueryServiceContainer() *apiKeyService.APIKeyQueryService {
+	var repository apiKeyDomainRepository.APIKeyQueryRepositoryInterface = &apiKeyRepository.APIKeyQueryRepository{
+		DBHandlerInterface: dbHandler,
+	}
+	if apiKeyMemoryRepository != nil {
+		repository = apiKeyMemoryRepository
+	}
+
+	service := &apiKeyService.APIKeyQueryService{
+		APIKeyQueryRepositoryInterface: repository,
+	}
+
+	return service
+}
+
+func (k *kernel) auditEventCommandServiceContainer() *auditService.AuditEventCommandService {
+	var repository auditDomainRepository.AuditEventCommandRepositoryInterface = &auditRepository.AuditEventCommandRepository{
+		DBHandlerInterface: dbHandler,
+	}
+	if auditEventMemoryRepository != nil {
+		repository = auditEventMemoryRepository
+	}
+
+	service := &auditService.AuditEventCommandService{
+		AuditEventCommandRepositoryInterface: repository,
+		AuthFailureStore:                     auditAuthFailureStore,
+	}
+
+	return service
+}
+
+func (k *kernel) auditEventQueryServiceContainer() *auditService.AuditEventQueryService {
+	var repository auditDomainRepository.AuditEventQueryRepositoryInterface = &auditRepository.AuditEventQueryRepository{
+		DBHandlerInterface: dbHandler,
+	}
+	if auditEventMemoryRepository != nil {
+		repository = auditEventMemoryRepository
+	}
+
+	service := &auditService.AuditEventQueryService{
+		AuditEventQueryRepositoryInterface: repository,
+	}
+
+	return service
+}
+
 func (k *kernel) recordCommandServiceContainer() *recordService.RecordCommandService {
-	repository := &recordRepository.RecordCommandRepository{
-		MySQLDBHandlerInterface: mysqlDBHandler,
+	var repository recordDomainRepository.RecordCommandRepositoryInterface = &recordRepository.RecordCommandRepository{
+		DBHandlerInterface: dbHandler,
+	}
+	if recordMemoryRepository != nil {
+		repository = recordMemoryRepository
 	}
 
-	service := &recordService.RecordCommandService{
-		RecordCommandRepositoryInterface: &recordRepository.RecordCommandRepositoryCircuitBreaker{
+	repository = &recordRepository.RecordCommandRepositoryCircuitBreaker{
+		RecordCommandRepositoryInterface: repository,
+	}
+	if recordCache != nil {
+		repository = &recordRepository.RecordCommandRepositoryCache{
 			RecordCommandRepositoryInterface: repository,
-		},
+			Backend:                          recordCache,
+		}
+	}
+
+	service := &recordService.RecordCommandService{
+		TransactionHandlerInterface:       transactionHandler,
+		RecordCommandRepositoryInterface:  repository,
+		AuditEventCommandServiceInterface: k.auditEventCommandServiceContainer(),
 	}
 
 	return service
 }
 
 func (k *kernel) recordQueryServiceContainer() *recordService.RecordQueryService {
-	repository := &recordRepository.RecordQueryRepository{
-		MySQLDBHandlerInterface: mysqlDBHandler,
+	var repository recordDomainRepository.RecordQueryRepositoryInterface = &recordRepository.RecordQueryRepository{
+		DBHandlerInterface: dbHandler,
+	}
+	if recordMemoryRepository != nil {
+		repository = recordMemoryRepository
 	}
 
-	service := &recordService.RecordQueryService{
-		RecordQueryRepositoryInterface: &recordRepository.RecordQueryRepositoryCircuitBreaker{
+	repository = &recordRepository.RecordQueryRepositoryCircuitBreaker{
+		RecordQueryRepositoryInterface: repository,
+		Stale:                          recordStaleCache,
+	}
+	if recordCache != nil {
+		repository = &recordRepository.RecordQueryRepositoryCache{
 			RecordQueryRepositoryInterface: repository,
-		},
+			Backend:                        recordCache,
+		}
+	}
+
+	service := &recordService.RecordQueryService{
+		RecordQueryRepositoryInterface: repository,
 	}
 
 	return service
@@ -123,17 +398,170 @@ func (k *kernel) recordQueryServiceContainer() *recordService.RecordQueryService
 func registerHandlers() {
 	var err error
 
+	// keep the records, api keys and audit events in memory, without any database
+	if os.Getenv("STORAGE_BACKEND") == "memory" {
+		apiKeyMemoryRepository = &apiKeyRepository.APIKeyMemoryRepository{}
+		auditEventMemoryRepository = &auditRepository.AuditEventMemoryRepository{}
+		recordMemoryRepository = &recordRepository.RecordMemoryRepository{}
+		transactionHandler = database.NoTransactionHandler{}
+	} else {
+		registerDatabase()
+		transactionHandler = dbHandler
+	}
+
+	// circuit breaker settings, applied once for every command
+	err = hystrix_config.Config{}.Configure()
+	if err != nil {
+		log.Fatalf("[SERVER] invalid circuit breaker config: %v", err)
+	}
+
+	// serve the last known records while the database is unavailable
+	if maxAge := envDuration("RECORD_STALE_TTL"); maxAge > 0 {
+		size, _ := strconv.Atoi(os.Getenv("RECORD_STALE_CACHE_SIZE"))
+		if size <= 0 {
+			size = 10000
+		}
+
+		recordStaleCache = recordRepository.NewRecordStaleCache(size, maxAge)
+	}
+
+	// read-through cache of the records, in front of the circuit breaker
+	ttl := envDuration("RECORD_CACHE_TTL")
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+
+	switch backend := os.Getenv("RECORD_CACHE"); backend {
+	case "":
+	case "memory":
+		size, _ := strconv.Atoi(os.Getenv("RECORD_CACHE_SIZE"))
+		if size <= 0 {
+			size = 10000
+		}
+
+		recordCache = cache.NewMemoryBackend(size, ttl)
+	case "redis":
+		redisDB, _ := strconv.Atoi(os.Getenv("REDIS_DB"))
+
+		recordCache = cache.NewRedisBackend(cacheTypes.RedisParams{
+			Addr:     os.Getenv("REDIS_ADDR"),
+			Password: os.Getenv("REDIS_PASSWORD"),
+			DB:       redisDB,
+			TTL:      ttl,
+			Prefix:   "gomora:",
+		})
+	default:
+		log.Fatalf("[SERVER] unknown record cache %q", backend)
+	}
+
+	// per client rate limits of the routes, shared by the instances through redis
+	rateLimits := map[string]ratelimitTypes.Limit{}
+	if enabled, _ := strconv.ParseBool(os.Getenv("RATE_LIMIT_ENABLED")); enabled {
+		rateLimits, err = ratelimit_config.Config{}.Limits()
+		if err != nil {
+			log.Fatalf("[SERVER] invalid rate limit config: %v", err)
+		}
+	}
+
+	var rateLimitStore ratelimitTypes.RateLimitStoreInterface
+	switch store := os.Getenv("RATE_LIMIT_STORE"); store {
+	case "", "memory":
+	case "redis":
+		redisDB, _ := strconv.Atoi(os.Getenv("REDIS_DB"))
+
+		rateLimitStore = ratelimit.NewRedisStore(redis.NewClient(redisTypes.ClientParams{
+			Addr:     os.Getenv("REDIS_ADDR"),
+			Password: os.Getenv("REDIS_PASSWORD"),
+			DB:       redisDB,
+		}), "gomora:ratelimit:")
+	default:
+		log.Fatalf("[SERVER] unknown rate limit store %q", store)
+	}
+
+	rateLimiter = ratelimit.NewLimiter(rateLimits, rateLimitStore)
+
+	// only the proxies in front of the server can tell the client ip
+	trustedProxies, err = requestinfo.ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		log.Fatalf("[SERVER] invalid trusted proxies: %v", err)
+	}
+
+	// deadlines of the requests, passed down to the circuit breakers
+	requestTimeouts, err = timeout_config.Config{}.Timeouts()
+	if err != nil {
+		log.Fatalf("[SERVER] invalid request timeout config: %v", err)
+	}
+
+	// adaptive concurrency limit shared by both servers, shedding the excess requests
+	if enabled, _ := strconv.ParseBool(os.Getenv("LOAD_SHED_ENABLED")); enabled {
+		initialLimit, _ := strconv.Atoi(os.Getenv("LOAD_SHED_INITIAL_LIMIT"))
+		minLimit, _ := strconv.Atoi(os.Getenv("LOAD_SHED_MIN_LIMIT"))
+		maxLimit, _ := strconv.Atoi(os.Getenv("LOAD_SHED_MAX_LIMIT"))
+
+		loadShedder = loadshed.NewLimiter(loadshedTypes.LimiterParams{
+			InitialLimit: initialLimit,
+			MinLimit:     minLimit,
+			MaxLimit:     maxLimit,
+			Latency:      envDuration("LOAD_SHED_LATENCY"),
+		})
+
+		expvar.Publish("loadshed", expvar.Func(func() interface{} {
+			return loadShedder.Stats()
+		}))
+	}
+
+	// maintenance mode on start, then switched from the admin endpoint or the file
+	if mode := os.Getenv("MAINTENANCE_MODE"); len(mode) > 0 {
+		if _, err := maintenance.Set(mode, ""); err != nil {
+			log.Fatalf("[SERVER] invalid maintenance mode %q", mode)
+		}
+	}
+	if path := os.Getenv("MAINTENANCE_FILE"); len(path) > 0 {
+		go maintenance.WatchFile(context.Background(), path, envDuration("MAINTENANCE_FILE_INTERVAL"))
+	}
+
+	// external identity provider
+	if issuer := os.Getenv("OIDC_ISSUER"); len(issuer) > 0 {
+		// without it, the tokens issued to any client of the provider would be accepted
+		if len(os.Getenv("OIDC_AUDIENCE")) == 0 {
+			log.Fatalf("[SERVER] OIDC_AUDIENCE is required with OIDC_ISSUER")
+		}
+
+		jwksCacheTTL, _ := time.ParseDuration(os.Getenv("OIDC_JWKS_CACHE_TTL"))
+
+		oidcProvider = oidc.NewOIDCProvider(oidcTypes.ProviderParams{
+			Issuer:       issuer,
+			Audience:     os.Getenv("OIDC_AUDIENCE"),
+			SubjectClaim: os.Getenv("OIDC_SUBJECT_CLAIM"),
+			ScopeClaim:   os.Getenv("OIDC_SCOPE_CLAIM"),
+			ScopeMapping: oidc.ParseScopeMapping(os.Getenv("OIDC_SCOPE_MAPPING")),
+			TenantClaim:  os.Getenv("OIDC_TENANT_CLAIM"),
+			JWKSCacheTTL: jwksCacheTTL,
+		})
+	}
+}
+
+// registerDatabase connects to the database, applying the pending migrations when asked
+func registerDatabase() {
 	// connect to database
-	mysqlDBHandler = &mysql.MySQLDBHandler{}
-	err = mysqlDBHandler.Connect(types.ConnectionParams{
-		DBHost:     os.Getenv("DB_HOST"),
-		DBPort:     os.Getenv("DB_PORT"),
-		DBDatabase: os.Getenv("DB_DATABASE"),
-		DBUsername: os.Getenv("DB_USERNAME"),
-		DBPassword: os.Getenv("DB_PASSWORD"),
-	})
+	var migrator *database.Migrator
+	var err error
+	dbHandler, migrator, err = ConnectDatabase()
 	if err != nil {
-		log.Fatalf("[SERVER] mysql database is not responding: %v", err)
+		log.Fatalf("[SERVER] database is not responding: %v (set DB_DRIVER=sqlite or STORAGE_BACKEND=memory to run without a database server)", err)
+	}
+
+	// export the connection pool statistics, served with the expvar handler
+	expvar.Publish("database", expvar.Func(func() interface{} {
+		return dbHandler.Stats()
+	}))
+
+	// apply the embedded schema migrations on start
+	if autoMigrate, _ := strconv.ParseBool(os.Getenv("DB_AUTO_MIGRATE")); autoMigrate {
+		err = migrator.Up(context.Background(), 0)
+		if err != nil && !errors.Is(err, database.ErrNoChange) {
+			log.Fatalf("[SERVER] schema migration failed: %v", err)
+		}
 	}
 }
 
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"gomora/internal/tenant"
+)
+
+const (
+	// ScopeAdmin is the scope that lifts the per-owner isolation of records
+	ScopeAdmin string = "admin"
+	// ScopeAudit is the scope allowed to read the audit log
+	ScopeAudit string = "audit"
+)
+
+type contextKey struct{}
+
+// Identity holds the authenticated caller of a request
+type Identity struct {
+	Subject  string
+	Scopes   []string
+	TenantID string // tenant the token is bound to, if any
+	APIKeyID string // api key the caller authenticated with, if any
+}
+
+// HasScope returns true when the identity was granted the given scope
+func (identity Identity) HasScope(scope string) bool {
+	for _, s := range identity.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsAdmin returns true when the identity holds the admin scope
+func (identity Identity) IsAdmin() bool {
+	return identity.HasScope(ScopeAdmin)
+}
+
+// IdentityFromClaims builds the identity from the jwt claims
+// The scopes are read from the space-delimited "scope" claim or the "scopes" array claim.
+func IdentityFromClaims(claims map[string]interface{}) Identity {
+	identity := Identity{}
+
+	if sub, ok := claims["sub"].(string); ok {
+		identity.Subject = sub
+	}
+
+	if tenantID, ok := claims[tenant.ClaimName].(string); ok {
+		identity.TenantID = tenantID
+	}
+
+	if scope, ok := claims["scope"].(string); ok {
+		identity.Scopes = append(identity.Scopes, strings.Fields(scope)...)
+	}
+
+	switch scopes := claims["scopes"].(type) {
+	case []string:
+		identity.Scopes = append(identity.Scopes, scopes...)
+	case []interface{}:
+		for _, s := range scopes {
+			if str, ok := s.(string); ok {
+				identity.Scopes = append(identity.Scopes, str)
+			}
+		}
+	}
+
+	return identity
+}
+
+// NewContext returns a copy of the context carrying the identity
+func NewContext(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, identity)
+}
+
+// FromContext returns the identity carried by the context
+func FromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(contextKey{}).(Identity)
+
+	return identity, ok
+}
@@ -0,0 +1,24 @@
+package errors
+
+// clientErrors are the codes caused by the request rather than by a failing dependency
+var clientErrors = map[string]bool{
+	DuplicateRecord:       true,
+	ForbiddenAccess:       true,
+	InvalidPayload:        true,
+	InvalidRequestPayload: true,
+	MaximumLimitReached:   true,
+	MissingAPIEndpoint:    true,
+	MissingRecord:         true,
+	RequestTimeout:        true, // the caller gave up, the dependency may be fine
+	UnauthorizedAccess:    true,
+}
+
+// IsInfrastructureError returns true when the error is a failure of a dependency, like the database
+// Only those count toward the circuit breakers, a missing or duplicate record must not trip them.
+func IsInfrastructureError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return !clientErrors[err.Error()]
+}
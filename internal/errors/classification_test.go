@@ -0,0 +1,23 @@
+package errors
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsInfrastructureError(t *testing.T) {
+	tests := map[error]bool{
+		nil:                           false,
+		errors.New(MissingRecord):     false,
+		errors.New(DuplicateRecord):   false,
+		errors.New(ForbiddenAccess):   false,
+		errors.New(DatabaseError):     true,
+		errors.New("connection lost"): true,
+	}
+
+	for err, expected := range tests {
+		if IsInfrastructureError(err) != expected {
+			t.Errorf("%v: expected %t", err, expected)
+		}
+	}
+}
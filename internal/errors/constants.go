@@ -21,8 +21,12 @@ const (
 	MissingConfiguration string = "MISSING_CONFIGURATION"
 	// MissingRecord is the code for no record found
 	MissingRecord string = "MISSING_RECORD"
+	// RequestTimeout is the code when the deadline of the request expired
+	RequestTimeout string = "REQUEST_TIMEOUT"
 	// ServerError is the code for server error
 	ServerError string = "SERVER_ERROR"
+	// ServerOverloaded is the code for requests shed under load
+	ServerOverloaded string = "SERVER_OVERLOADED"
 	// ServerMaintenance is the code for server maintenance
 	ServerMaintenance string = "SERVER_MAINTENANCE"
 	// StorageUploadFailed is the code when storage upload (like to s3) failed
@@ -0,0 +1,83 @@
+package requestinfo
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// TrustedProxies holds the addresses of the reverse proxies allowed to forward the client ip
+type TrustedProxies []netip.Prefix
+
+// ParseTrustedProxies parses the comma-delimited ip addresses and cidr blocks of the trusted proxies
+func ParseTrustedProxies(value string) (TrustedProxies, error) {
+	var proxies TrustedProxies
+
+	for _, proxy := range strings.Split(value, ",") {
+		if proxy = strings.TrimSpace(proxy); len(proxy) == 0 {
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(proxy)
+			if addrErr != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", proxy)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+
+		proxies = append(proxies, prefix.Masked())
+	}
+
+	return proxies, nil
+}
+
+// Trusts returns true when the ip is one of a trusted proxy
+func (proxies TrustedProxies) Trusts(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+
+	for _, prefix := range proxies {
+		if prefix.Contains(addr.Unmap()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ClientIP returns the ip of the client given the ip of the peer and the X-Forwarded-For and X-Real-IP headers
+// The headers are only read when the peer is a trusted proxy. The X-Forwarded-For entries are walked from the
+// right, the first one that is not a trusted proxy is the client.
+func (proxies TrustedProxies) ClientIP(peerIP string, forwardedFor []string, realIP string) string {
+	if !proxies.Trusts(peerIP) {
+		return peerIP
+	}
+
+	var hops []string
+	for _, value := range forwardedFor {
+		hops = append(hops, strings.Split(value, ",")...)
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if _, err := netip.ParseAddr(hop); err != nil {
+			break
+		}
+
+		if !proxies.Trusts(hop) {
+			return hop
+		}
+	}
+
+	if realIP = strings.TrimSpace(realIP); len(hops) == 0 {
+		if _, err := netip.ParseAddr(realIP); err == nil {
+			return realIP
+		}
+	}
+
+	return peerIP
+}
@@ -0,0 +1,38 @@
+package requestinfo
+
+import (
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies("10.0.0.0/8, 192.168.1.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		peer         string
+		forwardedFor []string
+		realIP       string
+		expected     string
+	}{
+		"direct client":               {peer: "203.0.113.7", expected: "203.0.113.7"},
+		"spoofed header":              {peer: "203.0.113.7", forwardedFor: []string{"1.2.3.4"}, realIP: "1.2.3.4", expected: "203.0.113.7"},
+		"trusted proxy":               {peer: "10.0.0.2", forwardedFor: []string{"203.0.113.7"}, expected: "203.0.113.7"},
+		"chain of proxies":            {peer: "10.0.0.2", forwardedFor: []string{"1.2.3.4, 203.0.113.7", "192.168.1.1"}, expected: "203.0.113.7"},
+		"real ip from trusted proxy":  {peer: "192.168.1.1", realIP: "203.0.113.7", expected: "203.0.113.7"},
+		"malformed forwarded address": {peer: "10.0.0.2", forwardedFor: []string{"unknown"}, expected: "10.0.0.2"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if ip := proxies.ClientIP(test.peer, test.forwardedFor, test.realIP); ip != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, ip)
+			}
+		})
+	}
+
+	if _, err := ParseTrustedProxies("10.0.0.0/33"); err == nil {
+		t.Error("expected an invalid proxy error")
+	}
+}
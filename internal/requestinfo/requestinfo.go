@@ -0,0 +1,25 @@
+package requestinfo
+
+import (
+	"context"
+)
+
+type contextKey struct{}
+
+// Info holds the transport details of a request
+type Info struct {
+	RequestID string
+	IP        string
+}
+
+// NewContext returns a copy of the context carrying the request info
+func NewContext(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, contextKey{}, info)
+}
+
+// FromContext returns the request info carried by the context
+func FromContext(ctx context.Context) Info {
+	info, _ := ctx.Value(contextKey{}).(Info)
+
+	return info
+}
@@ -0,0 +1,33 @@
+package stale
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type contextKey struct{}
+
+// Marker records whether the response was served from stale data, like a fallback cache
+type Marker struct {
+	stale atomic.Bool
+}
+
+// NewContext returns a copy of the context carrying a new marker
+// The transport creates it before calling the services, then checks it to flag the response.
+func NewContext(ctx context.Context) (context.Context, *Marker) {
+	marker := &Marker{}
+
+	return context.WithValue(ctx, contextKey{}, marker), marker
+}
+
+// Mark flags the response of the context as stale, if the transport asked for it
+func Mark(ctx context.Context) {
+	if marker, ok := ctx.Value(contextKey{}).(*Marker); ok {
+		marker.stale.Store(true)
+	}
+}
+
+// Stale returns true when the response was served from stale data
+func (m *Marker) Stale() bool {
+	return m.stale.Load()
+}
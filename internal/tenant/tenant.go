@@ -0,0 +1,80 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"os"
+	"regexp"
+)
+
+const (
+	// HeaderName is the REST header carrying the tenant
+	HeaderName string = "X-Tenant-ID"
+	// MetadataKey is the gRPC metadata key carrying the tenant
+	MetadataKey string = "x-tenant-id"
+	// ClaimName is the jwt claim carrying the tenant
+	ClaimName string = "tenant_id"
+	// DefaultTenantID is the tenant used when none is requested nor configured
+	DefaultTenantID string = "default"
+)
+
+var (
+	// ErrInvalidTenant is returned when the tenant id is malformed
+	ErrInvalidTenant = errors.New("invalid tenant id")
+	// ErrTenantMismatch is returned when the requested tenant differs from the token tenant
+	ErrTenantMismatch = errors.New("tenant does not match the token")
+
+	tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+)
+
+type contextKey struct{}
+
+// Default returns the tenant of callers that did not request any
+func Default() string {
+	if tenantID := os.Getenv("DEFAULT_TENANT_ID"); len(tenantID) > 0 {
+		return tenantID
+	}
+
+	return DefaultTenantID
+}
+
+// Validate returns ErrInvalidTenant when the tenant id is malformed
+func Validate(tenantID string) error {
+	if !tenantIDPattern.MatchString(tenantID) {
+		return ErrInvalidTenant
+	}
+
+	return nil
+}
+
+// Resolve returns the tenant of the request given by the token claim and the requested tenant
+// The tenant only comes from the claim, the tokens without one are pinned to the default tenant.
+// The requested tenant must match it.
+func Resolve(claimTenantID string, requestedTenantID string) (string, error) {
+	if len(requestedTenantID) > 0 && Validate(requestedTenantID) != nil {
+		return "", ErrInvalidTenant
+	}
+
+	tenantID := claimTenantID
+	if len(tenantID) == 0 {
+		tenantID = Default()
+	}
+
+	if len(requestedTenantID) > 0 && requestedTenantID != tenantID {
+		return "", ErrTenantMismatch
+	}
+
+	return tenantID, nil
+}
+
+// NewContext returns a copy of the context carrying the tenant
+func NewContext(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, tenantID)
+}
+
+// FromContext returns the tenant carried by the context
+func FromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(contextKey{}).(string)
+
+	return tenantID, ok && len(tenantID) > 0
+}
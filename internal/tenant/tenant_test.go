@@ -0,0 +1,33 @@
+package tenant
+
+import (
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	t.Setenv("DEFAULT_TENANT_ID", "acme")
+
+	tests := map[string]struct {
+		claim     string
+		requested string
+		expected  string
+		err       error
+	}{
+		"bound token":                {claim: "t1", expected: "t1"},
+		"bound token, same tenant":   {claim: "t1", requested: "t1", expected: "t1"},
+		"bound token, other tenant":  {claim: "t1", requested: "t2", err: ErrTenantMismatch},
+		"unbound token":              {expected: "acme"},
+		"unbound token, default":     {requested: "acme", expected: "acme"},
+		"unbound token, other":       {requested: "t2", err: ErrTenantMismatch},
+		"malformed requested tenant": {claim: "t1", requested: "t1/../t2", err: ErrInvalidTenant},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tenantID, err := Resolve(test.claim, test.requested)
+			if err != test.err || tenantID != test.expected {
+				t.Errorf("expected %q, %v, got %q, %v", test.expected, test.err, tenantID, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package application
+
+import (
+	"context"
+
+	"gomora/module/apikey/domain/entity"
+	"gomora/module/apikey/infrastructure/service/types"
+)
+
+// APIKeyCommandServiceInterface holds the implementable methods for the api key command service
+type APIKeyCommandServiceInterface interface {
+	// CreateAPIKey creates a new api key and returns the plain key
+	CreateAPIKey(ctx context.Context, data types.CreateAPIKey) (entity.APIKey, string, error)
+	// RevokeAPIKey revokes an api key
+	RevokeAPIKey(ctx context.Context, ID string) error
+}
@@ -0,0 +1,19 @@
+package application
+
+import (
+	"context"
+
+	"gomora/internal/auth"
+	"gomora/module/apikey/domain/entity"
+	"gomora/module/apikey/infrastructure/service/types"
+)
+
+// APIKeyQueryServiceInterface holds the implementable methods for the api key query service
+type APIKeyQueryServiceInterface interface {
+	// GetAPIKeyByID gets an api key by its ID
+	GetAPIKeyByID(ctx context.Context, ID string) (entity.APIKey, error)
+	// GetAPIKeys gets all the api keys
+	GetAPIKeys(ctx context.Context) ([]entity.APIKey, error)
+	// AuthenticateAPIKey verifies the api key and returns the identity it acts for
+	AuthenticateAPIKey(ctx context.Context, data types.AuthenticateAPIKey) (auth.Identity, error)
+}
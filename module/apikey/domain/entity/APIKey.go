@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"database/sql"
+	"net"
+	"strings"
+	"time"
+)
+
+// APIKey holds the api key entity fields
+// Only the hash of the key is stored, the key itself is shown once on creation.
+type APIKey struct {
+	ID         string
+	Prefix     string
+	Hash       string `db:"key_hash"`
+	Name       string
+	Subject    string
+	TenantID   string       `db:"tenant_id"`
+	Scopes     string       // space-delimited
+	AllowedIPs string       `db:"allowed_ips"` // comma-delimited ip addresses or cidr blocks
+	ExpiresAt  sql.NullTime `db:"expires_at"`
+	RevokedAt  sql.NullTime `db:"revoked_at"`
+	CreatedAt  time.Time    `db:"created_at"`
+}
+
+// GetModelName returns the model name of api key entity that can be used for naming schemas
+func (entity *APIKey) GetModelName() string {
+	return "api_keys"
+}
+
+// GetScopes returns the list of scopes granted to the api key
+func (entity *APIKey) GetScopes() []string {
+	return strings.Fields(entity.Scopes)
+}
+
+// GetAllowedIPs returns the list of ip addresses and cidr blocks allowed to use the api key
+func (entity *APIKey) GetAllowedIPs() []string {
+	var allowedIPs []string
+
+	for _, allowedIP := range strings.Split(entity.AllowedIPs, ",") {
+		if allowedIP = strings.TrimSpace(allowedIP); len(allowedIP) > 0 {
+			allowedIPs = append(allowedIPs, allowedIP)
+		}
+	}
+
+	return allowedIPs
+}
+
+// IsActive returns true when the api key is neither revoked nor expired
+func (entity *APIKey) IsActive(now time.Time) bool {
+	if entity.RevokedAt.Valid {
+		return false
+	}
+
+	return !entity.ExpiresAt.Valid || now.Before(entity.ExpiresAt.Time)
+}
+
+// AllowsIP returns true when the ip is within the allowlist, an empty allowlist allows any ip
+func (entity *APIKey) AllowsIP(ip string) bool {
+	allowedIPs := entity.GetAllowedIPs()
+	if len(allowedIPs) == 0 {
+		return true
+	}
+
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+
+	for _, allowedIP := range allowedIPs {
+		if _, network, err := net.ParseCIDR(allowedIP); err == nil {
+			if network.Contains(addr) {
+				return true
+			}
+
+			continue
+		}
+
+		if allowed := net.ParseIP(allowedIP); allowed != nil && allowed.Equal(addr) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestAllowsIP(t *testing.T) {
+	apiKey := APIKey{AllowedIPs: "10.0.0.0/24, 192.168.1.7"}
+
+	tests := map[string]bool{
+		"10.0.0.42":   true,
+		"10.0.1.42":   false,
+		"192.168.1.7": true,
+		"192.168.1.8": false,
+		"not an ip":   false,
+		"":            false,
+	}
+	for ip, expected := range tests {
+		if allowed := apiKey.AllowsIP(ip); allowed != expected {
+			t.Errorf("expected %s allowed %t, got %t", ip, expected, allowed)
+		}
+	}
+
+	// an empty allowlist allows any ip
+	if !(&APIKey{}).AllowsIP("203.0.113.7") {
+		t.Error("expected any ip to be allowed")
+	}
+}
+
+func TestIsActive(t *testing.T) {
+	now := time.Now()
+
+	tests := map[string]struct {
+		apiKey   APIKey
+		expected bool
+	}{
+		"no expiry":   {APIKey{}, true},
+		"not expired": {APIKey{ExpiresAt: sql.NullTime{Time: now.Add(time.Minute), Valid: true}}, true},
+		"expired":     {APIKey{ExpiresAt: sql.NullTime{Time: now, Valid: true}}, false},
+		"revoked":     {APIKey{RevokedAt: sql.NullTime{Time: now.Add(-time.Minute), Valid: true}}, false},
+	}
+
+	for name, test := range tests {
+		if active := test.apiKey.IsActive(now); active != test.expected {
+			t.Errorf("%s: expected active %t, got %t", name, test.expected, active)
+		}
+	}
+}
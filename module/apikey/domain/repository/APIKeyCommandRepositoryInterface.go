@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"gomora/module/apikey/domain/entity"
+	"gomora/module/apikey/infrastructure/repository/types"
+)
+
+// APIKeyCommandRepositoryInterface holds the implementable methods for api key command repository
+type APIKeyCommandRepositoryInterface interface {
+	// InsertAPIKey creates a new api key
+	InsertAPIKey(ctx context.Context, data types.CreateAPIKey) (entity.APIKey, error)
+	// RevokeAPIKey revokes an api key
+	RevokeAPIKey(ctx context.Context, data types.RevokeAPIKey) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"gomora/module/apikey/domain/entity"
+	"gomora/module/apikey/infrastructure/repository/types"
+)
+
+// APIKeyQueryRepositoryInterface holds the implementable methods for api key query repository
+type APIKeyQueryRepositoryInterface interface {
+	// SelectAPIKeyByID gets an api key by its ID
+	SelectAPIKeyByID(ctx context.Context, data types.SelectAPIKey) (entity.APIKey, error)
+	// SelectAPIKeys gets all the api keys
+	SelectAPIKeys(ctx context.Context, data types.SelectAPIKeys) ([]entity.APIKey, error)
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gomora/infrastructures/database"
+	"gomora/infrastructures/database/types"
+	apiError "gomora/internal/errors"
+	"gomora/module/apikey/domain/entity"
+	repositoryTypes "gomora/module/apikey/infrastructure/repository/types"
+)
+
+// APIKeyCommandRepository handles the api key command repository logic
+type APIKeyCommandRepository struct {
+	types.DBHandlerInterface
+}
+
+// InsertAPIKey creates a new api key
+func (repository *APIKeyCommandRepository) InsertAPIKey(ctx context.Context, data repositoryTypes.CreateAPIKey) (entity.APIKey, error) {
+	apiKey := entity.APIKey{
+		ID:         data.ID,
+		Prefix:     data.Prefix,
+		Hash:       data.Hash,
+		Name:       data.Name,
+		Subject:    data.Subject,
+		TenantID:   data.TenantID,
+		Scopes:     strings.Join(data.Scopes, " "),
+		AllowedIPs: strings.Join(data.AllowedIPs, ","),
+		CreatedAt:  time.Now(),
+	}
+
+	if data.ExpiresAt != nil {
+		apiKey.ExpiresAt = sql.NullTime{Time: *data.ExpiresAt, Valid: true}
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO %s (id, prefix, key_hash, name, subject, tenant_id, scopes, allowed_ips, expires_at) VALUES (:id, :prefix, :key_hash, :name, :subject, :tenant_id, :scopes, :allowed_ips, :expires_at)", apiKey.GetModelName())
+	// a retried insert that was applied before the connection was lost would fail as a duplicate
+	_, err := repository.DBHandlerInterface.Execute(database.WithoutRetry(ctx), stmt, apiKey)
+	if err != nil {
+		if errors.Is(err, database.ErrDuplicateEntry) {
+			return entity.APIKey{}, errors.New(apiError.DuplicateRecord)
+		}
+		return entity.APIKey{}, errors.New(apiError.DatabaseError)
+	}
+
+	return apiKey, nil
+}
+
+// RevokeAPIKey revokes an api key
+func (repository *APIKeyCommandRepository) RevokeAPIKey(ctx context.Context, data repositoryTypes.RevokeAPIKey) error {
+	var apiKey entity.APIKey
+
+	stmt := fmt.Sprintf("UPDATE %s SET revoked_at=CURRENT_TIMESTAMP WHERE id=:id AND revoked_at IS NULL", apiKey.GetModelName())
+	if len(data.TenantID) > 0 {
+		stmt += " AND tenant_id=:tenant_id"
+	}
+	res, err := repository.DBHandlerInterface.Execute(ctx, stmt, map[string]interface{}{
+		"id":        data.ID,
+		"tenant_id": data.TenantID,
+	})
+	if err != nil {
+		return errors.New(apiError.DatabaseError)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return errors.New(apiError.DatabaseError)
+	}
+	if affected == 0 {
+		return errors.New(apiError.MissingRecord)
+	}
+
+	return nil
+}
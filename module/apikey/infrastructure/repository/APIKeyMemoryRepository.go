@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	apiError "gomora/internal/errors"
+	"gomora/module/apikey/domain/entity"
+	repositoryTypes "gomora/module/apikey/infrastructure/repository/types"
+)
+
+// APIKeyMemoryRepository keeps the api keys in memory, for unit tests and demo mode
+// It implements both the command and the query repository with the semantics of the database ones.
+type APIKeyMemoryRepository struct {
+	mu      sync.RWMutex
+	apiKeys map[string]entity.APIKey
+}
+
+// InsertAPIKey creates a new api key
+func (repository *APIKeyMemoryRepository) InsertAPIKey(ctx context.Context, data repositoryTypes.CreateAPIKey) (entity.APIKey, error) {
+	apiKey := entity.APIKey{
+		ID:         data.ID,
+		Prefix:     data.Prefix,
+		Hash:       data.Hash,
+		Name:       data.Name,
+		Subject:    data.Subject,
+		TenantID:   data.TenantID,
+		Scopes:     strings.Join(data.Scopes, " "),
+		AllowedIPs: strings.Join(data.AllowedIPs, ","),
+		CreatedAt:  time.Now(),
+	}
+
+	if data.ExpiresAt != nil {
+		apiKey.ExpiresAt = sql.NullTime{Time: *data.ExpiresAt, Valid: true}
+	}
+
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	if _, ok := repository.apiKeys[apiKey.ID]; ok {
+		return entity.APIKey{}, errors.New(apiError.DuplicateRecord)
+	}
+
+	if repository.apiKeys == nil {
+		repository.apiKeys = map[string]entity.APIKey{}
+	}
+	repository.apiKeys[apiKey.ID] = apiKey
+
+	return apiKey, nil
+}
+
+// RevokeAPIKey revokes an api key
+func (repository *APIKeyMemoryRepository) RevokeAPIKey(ctx context.Context, data repositoryTypes.RevokeAPIKey) error {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	apiKey, ok := repository.apiKeys[data.ID]
+	if !ok || !inTenant(apiKey, data.TenantID) || apiKey.RevokedAt.Valid {
+		return errors.New(apiError.MissingRecord)
+	}
+
+	apiKey.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	repository.apiKeys[data.ID] = apiKey
+
+	return nil
+}
+
+// SelectAPIKeyByID select an api key by id
+func (repository *APIKeyMemoryRepository) SelectAPIKeyByID(ctx context.Context, data repositoryTypes.SelectAPIKey) (entity.APIKey, error) {
+	repository.mu.RLock()
+	defer repository.mu.RUnlock()
+
+	apiKey, ok := repository.apiKeys[data.ID]
+	if !ok || !inTenant(apiKey, data.TenantID) {
+		return entity.APIKey{}, errors.New(apiError.MissingRecord)
+	}
+
+	return apiKey, nil
+}
+
+// SelectAPIKeys select all the api keys
+func (repository *APIKeyMemoryRepository) SelectAPIKeys(ctx context.Context, data repositoryTypes.SelectAPIKeys) ([]entity.APIKey, error) {
+	apiKeys := []entity.APIKey{}
+
+	repository.mu.RLock()
+	for _, apiKey := range repository.apiKeys {
+		if inTenant(apiKey, data.TenantID) {
+			apiKeys = append(apiKeys, apiKey)
+		}
+	}
+	repository.mu.RUnlock()
+
+	sort.Slice(apiKeys, func(i, j int) bool { return apiKeys[i].CreatedAt.After(apiKeys[j].CreatedAt) })
+
+	return apiKeys, nil
+}
+
+// inTenant reports whether the api key belongs to the tenant, an empty tenant matches every key
+func inTenant(apiKey entity.APIKey, tenantID string) bool {
+	return len(tenantID) == 0 || apiKey.TenantID == tenantID
+}
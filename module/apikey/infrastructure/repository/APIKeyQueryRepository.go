@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"gomora/infrastructures/database/types"
+	apiError "gomora/internal/errors"
+	"gomora/module/apikey/domain/entity"
+	repositoryTypes "gomora/module/apikey/infrastructure/repository/types"
+)
+
+// APIKeyQueryRepository handles the api key query repository logic
+type APIKeyQueryRepository struct {
+	types.DBHandlerInterface
+}
+
+// SelectAPIKeyByID select an api key by id
+func (repository *APIKeyQueryRepository) SelectAPIKeyByID(ctx context.Context, data repositoryTypes.SelectAPIKey) (entity.APIKey, error) {
+	var apiKey entity.APIKey
+
+	stmt := fmt.Sprintf("SELECT * FROM %s WHERE id=:id", apiKey.GetModelName())
+	if len(data.TenantID) > 0 {
+		stmt += " AND tenant_id=:tenant_id"
+	}
+	err := repository.QueryRow(ctx, stmt, map[string]interface{}{
+		"id":        data.ID,
+		"tenant_id": data.TenantID,
+	}, &apiKey)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return apiKey, errors.New(apiError.MissingRecord)
+		}
+
+		return apiKey, errors.New(apiError.DatabaseError)
+	}
+
+	return apiKey, nil
+}
+
+// SelectAPIKeys select all the api keys
+func (repository *APIKeyQueryRepository) SelectAPIKeys(ctx context.Context, data repositoryTypes.SelectAPIKeys) ([]entity.APIKey, error) {
+	var apiKey entity.APIKey
+	apiKeys := []entity.APIKey{}
+
+	stmt := fmt.Sprintf("SELECT * FROM %s", apiKey.GetModelName())
+	if len(data.TenantID) > 0 {
+		stmt += " WHERE tenant_id=:tenant_id"
+	}
+	stmt += " ORDER BY created_at DESC"
+	err := repository.Query(ctx, stmt, map[string]interface{}{
+		"tenant_id": data.TenantID,
+	}, &apiKeys)
+	if err != nil {
+		return nil, errors.New(apiError.DatabaseError)
+	}
+
+	return apiKeys, nil
+}
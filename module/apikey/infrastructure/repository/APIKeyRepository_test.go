@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/ksuid"
+
+	"gomora/infrastructures/database/sqlite"
+	sqliteTypes "gomora/infrastructures/database/sqlite/types"
+	apiError "gomora/internal/errors"
+	repositoryTypes "gomora/module/apikey/infrastructure/repository/types"
+)
+
+func newSQLiteHandler(t *testing.T) *sqlite.SQLiteDBHandler {
+	t.Helper()
+
+	handler := &sqlite.SQLiteDBHandler{}
+	if err := handler.Connect(sqliteTypes.ConnectionParams{DBPath: sqlite.MemoryPath}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = handler.Close() })
+
+	migrator, err := sqlite.NewMigrator(handler.Conn)
+	if err == nil {
+		err = migrator.Up(context.Background(), 0)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return handler
+}
+
+func TestAPIKeysTenant(t *testing.T) {
+	handler := newSQLiteHandler(t)
+	commandRepository := &APIKeyCommandRepository{DBHandlerInterface: handler}
+	queryRepository := &APIKeyQueryRepository{DBHandlerInterface: handler}
+	ctx := context.Background()
+
+	IDs := map[string]string{}
+	for _, tenantID := range []string{"t1", "t2"} {
+		ID := ksuid.New().String()
+		_, err := commandRepository.InsertAPIKey(ctx, repositoryTypes.CreateAPIKey{
+			ID:       ID,
+			Prefix:   "gmk_" + ID,
+			Hash:     ID,
+			Name:     tenantID,
+			Subject:  "apikey:" + ID,
+			TenantID: tenantID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		IDs[tenantID] = ID
+	}
+
+	apiKeys, err := queryRepository.SelectAPIKeys(ctx, repositoryTypes.SelectAPIKeys{TenantID: "t1"})
+	if err != nil || len(apiKeys) != 1 || apiKeys[0].ID != IDs["t1"] {
+		t.Errorf("expected only %s, got %+v, %v", IDs["t1"], apiKeys, err)
+	}
+	apiKeys, err = queryRepository.SelectAPIKeys(ctx, repositoryTypes.SelectAPIKeys{})
+	if err != nil || len(apiKeys) != 2 {
+		t.Errorf("expected both keys, got %+v, %v", apiKeys, err)
+	}
+
+	if _, err := queryRepository.SelectAPIKeyByID(ctx, repositoryTypes.SelectAPIKey{ID: IDs["t2"], TenantID: "t1"}); err == nil || err.Error() != apiError.MissingRecord {
+		t.Errorf("expected %s, got %v", apiError.MissingRecord, err)
+	}
+	if err := commandRepository.RevokeAPIKey(ctx, repositoryTypes.RevokeAPIKey{ID: IDs["t2"], TenantID: "t1"}); err == nil || err.Error() != apiError.MissingRecord {
+		t.Errorf("expected %s, got %v", apiError.MissingRecord, err)
+	}
+
+	if err := commandRepository.RevokeAPIKey(ctx, repositoryTypes.RevokeAPIKey{ID: IDs["t2"], TenantID: "t2"}); err != nil {
+		t.Fatal(err)
+	}
+	apiKey, err := queryRepository.SelectAPIKeyByID(ctx, repositoryTypes.SelectAPIKey{ID: IDs["t2"]})
+	if err != nil || !apiKey.RevokedAt.Valid {
+		t.Errorf("expected %s to be revoked, got %+v, %v", IDs["t2"], apiKey, err)
+	}
+}
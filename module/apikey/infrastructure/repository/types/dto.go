@@ -0,0 +1,38 @@
+package types
+
+import (
+	"time"
+)
+
+// CreateAPIKey data struct for create api key repository
+type CreateAPIKey struct {
+	ID         string
+	Prefix     string
+	Hash       string
+	Name       string
+	Subject    string
+	TenantID   string
+	Scopes     []string
+	AllowedIPs []string
+	ExpiresAt  *time.Time
+}
+
+// SelectAPIKey data struct for select api key repository
+// An empty TenantID matches the keys of every tenant.
+type SelectAPIKey struct {
+	ID       string
+	TenantID string
+}
+
+// SelectAPIKeys data struct for select api keys repository
+// An empty TenantID matches the keys of every tenant.
+type SelectAPIKeys struct {
+	TenantID string
+}
+
+// RevokeAPIKey data struct for revoke api key repository
+// An empty TenantID matches the keys of every tenant.
+type RevokeAPIKey struct {
+	ID       string
+	TenantID string
+}
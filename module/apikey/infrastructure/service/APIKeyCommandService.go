@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/segmentio/ksuid"
+
+	"gomora/internal/auth"
+	apiError "gomora/internal/errors"
+	"gomora/module/apikey/domain/entity"
+	"gomora/module/apikey/domain/repository"
+	repositoryTypes "gomora/module/apikey/infrastructure/repository/types"
+	"gomora/module/apikey/infrastructure/service/types"
+	auditApplication "gomora/module/audit/application"
+	auditEntity "gomora/module/audit/domain/entity"
+	auditTypes "gomora/module/audit/infrastructure/service/types"
+)
+
+// KeyPrefix is prepended to every api key so they can be recognized in logs and secret scanners
+const KeyPrefix string = "gmk"
+
+// APIKeyCommandService handles the api key command service logic
+type APIKeyCommandService struct {
+	repository.APIKeyCommandRepositoryInterface
+	auditApplication.AuditEventCommandServiceInterface
+}
+
+// CreateAPIKey creates an api key and returns it along with the plain key
+// The plain key is not stored and cannot be retrieved afterwards. The keys created by a caller bound to a tenant
+// are bound to the same tenant.
+func (service *APIKeyCommandService) CreateAPIKey(ctx context.Context, data types.CreateAPIKey) (entity.APIKey, string, error) {
+	ID := ksuid.New().String()
+
+	if identity, ok := auth.FromContext(ctx); ok && len(identity.TenantID) > 0 {
+		if len(data.TenantID) > 0 && data.TenantID != identity.TenantID {
+			err := errors.New(apiError.ForbiddenAccess)
+			_ = service.AuditEventCommandServiceInterface.RecordAuditEvent(ctx, auditTypes.NewRecordAuditEvent(auditEntity.ActionAPIKeyCreate, ID, err))
+
+			return entity.APIKey{}, "", err
+		}
+
+		data.TenantID = identity.TenantID
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return entity.APIKey{}, "", err
+	}
+
+	key := fmt.Sprintf("%s_%s_%s", KeyPrefix, ID, hex.EncodeToString(secret))
+
+	apiKey := repositoryTypes.CreateAPIKey{
+		ID:         ID,
+		Prefix:     fmt.Sprintf("%s_%s", KeyPrefix, ID),
+		Hash:       hashKey(key),
+		Name:       data.Name,
+		Subject:    data.Subject,
+		TenantID:   data.TenantID,
+		Scopes:     data.Scopes,
+		AllowedIPs: data.AllowedIPs,
+		ExpiresAt:  data.ExpiresAt,
+	}
+
+	// keys without subject act on their own behalf
+	if len(apiKey.Subject) == 0 {
+		apiKey.Subject = fmt.Sprintf("apikey:%s", ID)
+	}
+
+	res, err := service.APIKeyCommandRepositoryInterface.InsertAPIKey(ctx, apiKey)
+	_ = service.AuditEventCommandServiceInterface.RecordAuditEvent(ctx, auditTypes.NewRecordAuditEvent(auditEntity.ActionAPIKeyCreate, ID, err))
+	if err != nil {
+		return entity.APIKey{}, "", err
+	}
+
+	return res, key, nil
+}
+
+// RevokeAPIKey revokes an api key
+// The callers bound to a tenant only revoke the keys of their tenant, the others are missing.
+func (service *APIKeyCommandService) RevokeAPIKey(ctx context.Context, ID string) error {
+	err := service.APIKeyCommandRepositoryInterface.RevokeAPIKey(ctx, repositoryTypes.RevokeAPIKey{
+		ID:       ID,
+		TenantID: tenantOf(ctx),
+	})
+	_ = service.AuditEventCommandServiceInterface.RecordAuditEvent(ctx, auditTypes.NewRecordAuditEvent(auditEntity.ActionAPIKeyRevoke, ID, err))
+
+	return err
+}
+
+// tenantOf returns the tenant the caller is bound to, empty when it isn't bound to one
+func tenantOf(ctx context.Context) string {
+	identity, _ := auth.FromContext(ctx)
+
+	return identity.TenantID
+}
+
+// hashKey returns the hash of the api key stored at rest
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
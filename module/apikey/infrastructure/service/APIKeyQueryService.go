@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
+
+	"gomora/infrastructures/database"
+	"gomora/internal/auth"
+	apiError "gomora/internal/errors"
+	"gomora/module/apikey/domain/entity"
+	"gomora/module/apikey/domain/repository"
+	repositoryTypes "gomora/module/apikey/infrastructure/repository/types"
+	"gomora/module/apikey/infrastructure/service/types"
+)
+
+// APIKeyQueryService handles the api key query service logic
+type APIKeyQueryService struct {
+	repository.APIKeyQueryRepositoryInterface
+}
+
+// GetAPIKeyByID retrieves the api key provided by its id
+// The callers bound to a tenant only read the keys of their tenant, the others are missing.
+func (service *APIKeyQueryService) GetAPIKeyByID(ctx context.Context, ID string) (entity.APIKey, error) {
+	return service.APIKeyQueryRepositoryInterface.SelectAPIKeyByID(ctx, repositoryTypes.SelectAPIKey{
+		ID:       ID,
+		TenantID: tenantOf(ctx),
+	})
+}
+
+// GetAPIKeys retrieves all the api keys
+// The callers bound to a tenant only read the keys of their tenant.
+func (service *APIKeyQueryService) GetAPIKeys(ctx context.Context) ([]entity.APIKey, error) {
+	return service.APIKeyQueryRepositoryInterface.SelectAPIKeys(ctx, repositoryTypes.SelectAPIKeys{
+		TenantID: tenantOf(ctx),
+	})
+}
+
+// AuthenticateAPIKey verifies the api key and returns the identity it acts for
+func (service *APIKeyQueryService) AuthenticateAPIKey(ctx context.Context, data types.AuthenticateAPIKey) (auth.Identity, error) {
+	// key format is <prefix>_<id>_<secret>
+	parts := strings.SplitN(data.Key, "_", 3)
+	if len(parts) != 3 || parts[0] != KeyPrefix || len(parts[1]) == 0 || len(parts[2]) == 0 {
+		return auth.Identity{}, errors.New(apiError.UnauthorizedAccess)
+	}
+
+	// read from the primary so that a revocation takes effect immediately
+	apiKey, err := service.APIKeyQueryRepositoryInterface.SelectAPIKeyByID(database.WithPrimary(ctx), repositoryTypes.SelectAPIKey{ID: parts[1]})
+	if err != nil {
+		if err.Error() == apiError.MissingRecord {
+			return auth.Identity{}, errors.New(apiError.UnauthorizedAccess)
+		}
+
+		return auth.Identity{}, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashKey(data.Key)), []byte(apiKey.Hash)) != 1 || !apiKey.IsActive(time.Now()) {
+		return auth.Identity{}, errors.New(apiError.UnauthorizedAccess)
+	}
+
+	if !apiKey.AllowsIP(data.IP) {
+		return auth.Identity{}, errors.New(apiError.ForbiddenAccess)
+	}
+
+	return auth.Identity{
+		Subject:  apiKey.Subject,
+		Scopes:   apiKey.GetScopes(),
+		TenantID: apiKey.TenantID,
+		APIKeyID: apiKey.ID,
+	}, nil
+}
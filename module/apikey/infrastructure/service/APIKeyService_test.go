@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gomora/internal/auth"
+	apiError "gomora/internal/errors"
+	"gomora/module/apikey/infrastructure/repository"
+	"gomora/module/apikey/infrastructure/service/types"
+	auditTypes "gomora/module/audit/infrastructure/service/types"
+)
+
+// auditor drops the audit events
+type auditor struct{}
+
+func (auditor) RecordAuditEvent(ctx context.Context, data auditTypes.RecordAuditEvent) error {
+	return nil
+}
+
+func newServices() (*APIKeyCommandService, *APIKeyQueryService) {
+	apiKeys := &repository.APIKeyMemoryRepository{}
+
+	return &APIKeyCommandService{APIKeyCommandRepositoryInterface: apiKeys, AuditEventCommandServiceInterface: auditor{}},
+		&APIKeyQueryService{APIKeyQueryRepositoryInterface: apiKeys}
+}
+
+func TestHashKey(t *testing.T) {
+	hash := hashKey("gmk_id_secret")
+	if len(hash) != 64 || hash != hashKey("gmk_id_secret") || hash == hashKey("gmk_id_secreT") {
+		t.Errorf("unexpected hash %s", hash)
+	}
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	commandService, queryService := newServices()
+	ctx := context.Background()
+
+	apiKey, key, err := commandService.CreateAPIKey(ctx, types.CreateAPIKey{
+		Name:       "integration",
+		TenantID:   "t1",
+		Scopes:     []string{"admin"},
+		AllowedIPs: []string{"10.0.0.0/24"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if apiKey.Hash == key || !strings.HasPrefix(key, apiKey.Prefix+"_") {
+		t.Fatalf("unexpected key %s of %+v", key, apiKey)
+	}
+
+	identity, err := queryService.AuthenticateAPIKey(ctx, types.AuthenticateAPIKey{Key: key, IP: "10.0.0.7"})
+	if err != nil || identity.Subject != "apikey:"+apiKey.ID || identity.TenantID != "t1" || !identity.IsAdmin() || identity.APIKeyID != apiKey.ID {
+		t.Fatalf("unexpected identity %+v, %v", identity, err)
+	}
+
+	tests := map[string]struct {
+		key  string
+		ip   string
+		code string
+	}{
+		"outside the allowlist": {key: key, ip: "10.0.1.7", code: apiError.ForbiddenAccess},
+		"wrong secret":          {key: key[:len(key)-1] + "0", ip: "10.0.0.7", code: apiError.UnauthorizedAccess},
+		"unknown key":           {key: KeyPrefix + "_unknown_secret", ip: "10.0.0.7", code: apiError.UnauthorizedAccess},
+		"malformed key":         {key: "secret", ip: "10.0.0.7", code: apiError.UnauthorizedAccess},
+	}
+	for name, test := range tests {
+		if _, err := queryService.AuthenticateAPIKey(ctx, types.AuthenticateAPIKey{Key: test.key, IP: test.ip}); err == nil || err.Error() != test.code {
+			t.Errorf("%s: expected %s, got %v", name, test.code, err)
+		}
+	}
+
+	// the revoked keys are rejected right away
+	if err := commandService.RevokeAPIKey(ctx, apiKey.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := queryService.AuthenticateAPIKey(ctx, types.AuthenticateAPIKey{Key: key, IP: "10.0.0.7"}); err == nil || err.Error() != apiError.UnauthorizedAccess {
+		t.Errorf("expected %s, got %v", apiError.UnauthorizedAccess, err)
+	}
+}
+
+func TestCreateAPIKeyTenant(t *testing.T) {
+	commandService, _ := newServices()
+	ctx := auth.NewContext(context.Background(), auth.Identity{Subject: "alice", TenantID: "t1", Scopes: []string{auth.ScopeAdmin}})
+
+	// the admins bound to a tenant can't create keys for another one
+	_, _, err := commandService.CreateAPIKey(ctx, types.CreateAPIKey{Name: "other", TenantID: "t2", Scopes: []string{auth.ScopeAdmin}})
+	if err == nil || err.Error() != apiError.ForbiddenAccess {
+		t.Errorf("expected %s, got %v", apiError.ForbiddenAccess, err)
+	}
+
+	// and their keys are bound to their tenant
+	apiKey, _, err := commandService.CreateAPIKey(ctx, types.CreateAPIKey{Name: "own", Scopes: []string{auth.ScopeAdmin}})
+	if err != nil || apiKey.TenantID != "t1" {
+		t.Errorf("expected a key bound to t1, got %+v, %v", apiKey, err)
+	}
+}
+
+func TestAPIKeysTenant(t *testing.T) {
+	commandService, queryService := newServices()
+	alice := auth.NewContext(context.Background(), auth.Identity{Subject: "alice", TenantID: "t1", Scopes: []string{auth.ScopeAdmin}})
+	bob := auth.NewContext(context.Background(), auth.Identity{Subject: "bob", TenantID: "t2", Scopes: []string{auth.ScopeAdmin}})
+
+	own, _, err := commandService.CreateAPIKey(alice, types.CreateAPIKey{Name: "own"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _, err := commandService.CreateAPIKey(bob, types.CreateAPIKey{Name: "other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the admins bound to a tenant only list the keys of their tenant
+	apiKeys, err := queryService.GetAPIKeys(alice)
+	if err != nil || len(apiKeys) != 1 || apiKeys[0].ID != own.ID {
+		t.Errorf("expected only %s, got %+v, %v", own.ID, apiKeys, err)
+	}
+
+	// the keys of another tenant are missing to them
+	if _, err := queryService.GetAPIKeyByID(alice, other.ID); err == nil || err.Error() != apiError.MissingRecord {
+		t.Errorf("expected %s, got %v", apiError.MissingRecord, err)
+	}
+	if err := commandService.RevokeAPIKey(alice, other.ID); err == nil || err.Error() != apiError.MissingRecord {
+		t.Errorf("expected %s, got %v", apiError.MissingRecord, err)
+	}
+	if apiKey, err := queryService.GetAPIKeyByID(bob, other.ID); err != nil || apiKey.RevokedAt.Valid {
+		t.Errorf("expected %s to be left active, got %+v, %v", other.ID, apiKey, err)
+	}
+
+	// while the admins bound to no tenant see them all
+	apiKeys, err = queryService.GetAPIKeys(context.Background())
+	if err != nil || len(apiKeys) != 2 {
+		t.Errorf("expected both keys, got %+v, %v", apiKeys, err)
+	}
+	if err := commandService.RevokeAPIKey(context.Background(), other.ID); err != nil {
+		t.Errorf("expected %s to be revoked, got %v", other.ID, err)
+	}
+}
@@ -0,0 +1,21 @@
+package types
+
+import (
+	"time"
+)
+
+// CreateAPIKey service types for create api key
+type CreateAPIKey struct {
+	Name       string
+	Subject    string
+	TenantID   string
+	Scopes     []string
+	AllowedIPs []string
+	ExpiresAt  *time.Time
+}
+
+// AuthenticateAPIKey service types for authenticate api key
+type AuthenticateAPIKey struct {
+	Key string
+	IP  string
+}
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	Validate         *validator.Validate = validator.New(validator.WithRequiredStructEnabled())
+	ValidationErrors map[string]string   = map[string]string{
+		"CreateAPIKeyRequest.Name":       "Name field is required.",
+		"CreateAPIKeyRequest.Scopes":     "Scopes must not contain spaces.",
+		"CreateAPIKeyRequest.AllowedIPs": "Allowed IPs must be IP addresses or CIDR blocks.",
+		"CreateAPIKeyRequest.ExpiresAt":  "Expiry must be in the future.",
+	}
+)
+
+// CreateAPIKeyRequest request struct for create api key
+type CreateAPIKeyRequest struct {
+	Name       string   `json:"name" validate:"required"`
+	Subject    string   `json:"subject"`
+	TenantID   string   `json:"tenantId"`
+	Scopes     []string `json:"scopes" validate:"dive,excludesall= "`
+	AllowedIPs []string `json:"allowedIps" validate:"dive,ip|cidr"`
+	ExpiresAt  *int64   `json:"expiresAt"`
+}
+
+// APIKeyResponse response struct
+type APIKeyResponse struct {
+	ID         string   `json:"id"`
+	Prefix     string   `json:"prefix"`
+	Name       string   `json:"name"`
+	Subject    string   `json:"subject"`
+	TenantID   string   `json:"tenantId"`
+	Scopes     []string `json:"scopes"`
+	AllowedIPs []string `json:"allowedIps"`
+	ExpiresAt  *int64   `json:"expiresAt"`
+	RevokedAt  *int64   `json:"revokedAt"`
+	CreatedAt  int64    `json:"createdAt"`
+}
+
+// CreateAPIKeyResponse response struct
+// Key is only returned once, on creation.
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
@@ -0,0 +1,187 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+
+	"gomora/interfaces/http/rest/viewmodels"
+	"gomora/internal/errors"
+	apiError "gomora/internal/errors"
+	"gomora/internal/tenant"
+	"gomora/module/apikey/application"
+	serviceTypes "gomora/module/apikey/infrastructure/service/types"
+	types "gomora/module/apikey/interfaces/http"
+)
+
+// APIKeyCommandController request controller for api key command
+type APIKeyCommandController struct {
+	application.APIKeyCommandServiceInterface
+}
+
+// CreateAPIKey request handler to create api key
+func (controller *APIKeyCommandController) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var request types.CreateAPIKeyRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		response := viewmodels.HTTPResponseVM{
+			Status:    http.StatusBadRequest,
+			Success:   false,
+			Message:   "Invalid payload request.",
+			ErrorCode: apiError.InvalidRequestPayload,
+		}
+
+		response.JSON(w)
+		return
+	}
+
+	// validate request
+	err := types.Validate.Struct(request)
+	if err != nil {
+		errors := err.(validator.ValidationErrors)
+		if len(errors) > 0 {
+			response := viewmodels.HTTPResponseVM{
+				Status:    http.StatusBadRequest,
+				Success:   false,
+				Message:   types.ValidationErrors[errors[0].StructNamespace()],
+				ErrorCode: apiError.InvalidPayload,
+			}
+
+			response.JSON(w)
+			return
+		}
+
+		response := viewmodels.HTTPResponseVM{
+			Status:    http.StatusBadRequest,
+			Success:   false,
+			Message:   "Invalid payload request.",
+			ErrorCode: apiError.InvalidRequestPayload,
+		}
+
+		response.JSON(w)
+		return
+	}
+
+	apiKey := serviceTypes.CreateAPIKey{
+		Name:       request.Name,
+		Subject:    request.Subject,
+		TenantID:   request.TenantID,
+		Scopes:     request.Scopes,
+		AllowedIPs: request.AllowedIPs,
+	}
+
+	if len(apiKey.TenantID) > 0 {
+		if err := tenant.Validate(apiKey.TenantID); err != nil {
+			response := viewmodels.HTTPResponseVM{
+				Status:    http.StatusBadRequest,
+				Success:   false,
+				Message:   "Invalid tenant ID.",
+				ErrorCode: apiError.InvalidPayload,
+			}
+
+			response.JSON(w)
+			return
+		}
+	}
+
+	if request.ExpiresAt != nil {
+		expiresAt := time.Unix(*request.ExpiresAt, 0)
+		if !expiresAt.After(time.Now()) {
+			response := viewmodels.HTTPResponseVM{
+				Status:    http.StatusBadRequest,
+				Success:   false,
+				Message:   types.ValidationErrors["CreateAPIKeyRequest.ExpiresAt"],
+				ErrorCode: apiError.InvalidPayload,
+			}
+
+			response.JSON(w)
+			return
+		}
+
+		apiKey.ExpiresAt = &expiresAt
+	}
+
+	res, key, err := controller.APIKeyCommandServiceInterface.CreateAPIKey(r.Context(), apiKey)
+	if err != nil {
+		var httpCode int
+		var errorMsg string
+
+		switch err.Error() {
+		case errors.DatabaseError:
+			httpCode = http.StatusInternalServerError
+			errorMsg = "Error occurred while saving API key."
+		case errors.ForbiddenAccess:
+			httpCode = http.StatusForbidden
+			errorMsg = "API keys can only be created for your own tenant."
+		default:
+			httpCode = http.StatusInternalServerError
+			errorMsg = "Please contact technical support."
+		}
+
+		response := viewmodels.HTTPResponseVM{
+			Status:    httpCode,
+			Success:   false,
+			Message:   errorMsg,
+			ErrorCode: err.Error(),
+		}
+
+		response.JSON(w)
+		return
+	}
+
+	response := viewmodels.HTTPResponseVM{
+		Status:  http.StatusOK,
+		Success: true,
+		Message: "Successfully created API key.",
+		Data: &types.CreateAPIKeyResponse{
+			APIKeyResponse: toAPIKeyResponse(res),
+			Key:            key,
+		},
+	}
+
+	response.JSON(w)
+}
+
+// RevokeAPIKey request handler to revoke api key
+func (controller *APIKeyCommandController) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	apiKeyID := chi.URLParam(r, "id")
+
+	err := controller.APIKeyCommandServiceInterface.RevokeAPIKey(r.Context(), apiKeyID)
+	if err != nil {
+		var httpCode int
+		var errorMsg string
+
+		switch err.Error() {
+		case errors.DatabaseError:
+			httpCode = http.StatusInternalServerError
+			errorMsg = "Error occurred while revoking API key."
+		case errors.MissingRecord:
+			httpCode = http.StatusNotFound
+			errorMsg = "No active API key found."
+		default:
+			httpCode = http.StatusInternalServerError
+			errorMsg = "Please contact technical support."
+		}
+
+		response := viewmodels.HTTPResponseVM{
+			Status:    httpCode,
+			Success:   false,
+			Message:   errorMsg,
+			ErrorCode: err.Error(),
+		}
+
+		response.JSON(w)
+		return
+	}
+
+	response := viewmodels.HTTPResponseVM{
+		Status:  http.StatusOK,
+		Success: true,
+		Message: "Successfully revoked API key.",
+	}
+
+	response.JSON(w)
+}
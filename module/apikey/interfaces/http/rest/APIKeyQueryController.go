@@ -0,0 +1,130 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"gomora/interfaces/http/rest/viewmodels"
+	"gomora/internal/errors"
+	"gomora/module/apikey/application"
+	"gomora/module/apikey/domain/entity"
+	types "gomora/module/apikey/interfaces/http"
+)
+
+// APIKeyQueryController request controller for api key query
+type APIKeyQueryController struct {
+	application.APIKeyQueryServiceInterface
+}
+
+// GetAPIKeys retrieves all the api keys
+func (controller *APIKeyQueryController) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	res, err := controller.APIKeyQueryServiceInterface.GetAPIKeys(r.Context())
+	if err != nil {
+		var httpCode int
+		var errorMsg string
+
+		switch err.Error() {
+		case errors.DatabaseError:
+			httpCode = http.StatusInternalServerError
+			errorMsg = "Error while fetching API keys."
+		default:
+			httpCode = http.StatusInternalServerError
+			errorMsg = "Please contact technical support."
+		}
+
+		response := viewmodels.HTTPResponseVM{
+			Status:    httpCode,
+			Success:   false,
+			Message:   errorMsg,
+			ErrorCode: err.Error(),
+		}
+
+		response.JSON(w)
+		return
+	}
+
+	apiKeys := []types.APIKeyResponse{}
+	for _, apiKey := range res {
+		apiKeys = append(apiKeys, toAPIKeyResponse(apiKey))
+	}
+
+	response := viewmodels.HTTPResponseVM{
+		Status:  http.StatusOK,
+		Success: true,
+		Message: "API keys successfully fetched.",
+		Data:    apiKeys,
+	}
+
+	response.JSON(w)
+}
+
+// GetAPIKeyByID retrieves the api key from the rest request
+func (controller *APIKeyQueryController) GetAPIKeyByID(w http.ResponseWriter, r *http.Request) {
+	apiKeyID := chi.URLParam(r, "id")
+
+	res, err := controller.APIKeyQueryServiceInterface.GetAPIKeyByID(r.Context(), apiKeyID)
+	if err != nil {
+		var httpCode int
+		var errorMsg string
+
+		switch err.Error() {
+		case errors.DatabaseError:
+			httpCode = http.StatusInternalServerError
+			errorMsg = "Error while fetching API key."
+		case errors.MissingRecord:
+			httpCode = http.StatusNotFound
+			errorMsg = "No API key found."
+		default:
+			httpCode = http.StatusInternalServerError
+			errorMsg = "Please contact technical support."
+		}
+
+		response := viewmodels.HTTPResponseVM{
+			Status:    httpCode,
+			Success:   false,
+			Message:   errorMsg,
+			ErrorCode: err.Error(),
+		}
+
+		response.JSON(w)
+		return
+	}
+
+	apiKey := toAPIKeyResponse(res)
+
+	response := viewmodels.HTTPResponseVM{
+		Status:  http.StatusOK,
+		Success: true,
+		Message: "API key successfully fetched.",
+		Data:    &apiKey,
+	}
+
+	response.JSON(w)
+}
+
+// toAPIKeyResponse maps the api key entity to its response, the key hash is never exposed
+func toAPIKeyResponse(apiKey entity.APIKey) types.APIKeyResponse {
+	res := types.APIKeyResponse{
+		ID:         apiKey.ID,
+		Prefix:     apiKey.Prefix,
+		Name:       apiKey.Name,
+		Subject:    apiKey.Subject,
+		TenantID:   apiKey.TenantID,
+		Scopes:     apiKey.GetScopes(),
+		AllowedIPs: apiKey.GetAllowedIPs(),
+		CreatedAt:  apiKey.CreatedAt.Unix(),
+	}
+
+	if apiKey.ExpiresAt.Valid {
+		expiresAt := apiKey.ExpiresAt.Time.Unix()
+		res.ExpiresAt = &expiresAt
+	}
+
+	if apiKey.RevokedAt.Valid {
+		revokedAt := apiKey.RevokedAt.Time.Unix()
+		res.RevokedAt = &revokedAt
+	}
+
+	return res
+}
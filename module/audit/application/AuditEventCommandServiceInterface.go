@@ -0,0 +1,13 @@
+package application
+
+import (
+	"context"
+
+	"gomora/module/audit/infrastructure/service/types"
+)
+
+// AuditEventCommandServiceInterface holds the implementable methods for the audit event command service
+type AuditEventCommandServiceInterface interface {
+	// RecordAuditEvent appends an audit event for the caller of the context
+	RecordAuditEvent(ctx context.Context, data types.RecordAuditEvent) error
+}
@@ -0,0 +1,16 @@
+package application
+
+import (
+	"context"
+
+	"gomora/module/audit/domain/entity"
+	"gomora/module/audit/infrastructure/service/types"
+)
+
+// AuditEventQueryServiceInterface holds the implementable methods for the audit event query service
+type AuditEventQueryServiceInterface interface {
+	// GetAuditEvents gets a page of the audit events matching the filters
+	GetAuditEvents(ctx context.Context, data types.GetAuditEvents) ([]entity.AuditEvent, error)
+	// ExportAuditEvents passes every audit event matching the filters to the callback
+	ExportAuditEvents(ctx context.Context, data types.GetAuditEvents, fn func(entity.AuditEvent) error) error
+}
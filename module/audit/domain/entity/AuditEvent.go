@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"time"
+)
+
+const (
+	// ActionAuthFailure is the action of a rejected authentication
+	ActionAuthFailure string = "auth.failure"
+	// ActionTokenGenerate is the action of a token issuance
+	ActionTokenGenerate string = "token.generate"
+	// ActionRecordCreate is the action of a record creation
+	ActionRecordCreate string = "record.create"
+	// ActionAPIKeyCreate is the action of an api key creation
+	ActionAPIKeyCreate string = "apikey.create"
+	// ActionAPIKeyRevoke is the action of an api key revocation
+	ActionAPIKeyRevoke string = "apikey.revoke"
+	// ActionCircuitForceOpen is the action of a circuit forced open
+	ActionCircuitForceOpen string = "circuit.force_open"
+	// ActionCircuitReset is the action of a circuit reset
+	ActionCircuitReset string = "circuit.reset"
+	// ActionMaintenanceSet is the action of a maintenance mode switch
+	ActionMaintenanceSet string = "maintenance.set"
+
+	// OutcomeSuccess is the outcome of a completed action
+	OutcomeSuccess string = "success"
+	// OutcomeFailure is the outcome of a failed or rejected action
+	OutcomeFailure string = "failure"
+)
+
+// AuditEvent holds the audit event entity fields
+// Audit events are append-only, they are never updated nor deleted.
+type AuditEvent struct {
+	ID         string
+	OccurredAt time.Time `db:"occurred_at"`
+	Actor      string
+	TenantID   string `db:"tenant_id"`
+	IP         string
+	RequestID  string `db:"request_id"`
+	Action     string
+	ResourceID string `db:"resource_id"`
+	Outcome    string
+	ErrorCode  string `db:"error_code"`
+}
+
+// GetModelName returns the model name of audit event entity that can be used for naming schemas
+func (entity *AuditEvent) GetModelName() string {
+	return "audit_events"
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"gomora/module/audit/domain/entity"
+	"gomora/module/audit/infrastructure/repository/types"
+)
+
+// AuditEventCommandRepositoryInterface holds the implementable methods for audit event command repository
+type AuditEventCommandRepositoryInterface interface {
+	// InsertAuditEvent appends a new audit event
+	InsertAuditEvent(ctx context.Context, data types.CreateAuditEvent) (entity.AuditEvent, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"gomora/module/audit/domain/entity"
+	"gomora/module/audit/infrastructure/repository/types"
+)
+
+// AuditEventQueryRepositoryInterface holds the implementable methods for audit event query repository
+type AuditEventQueryRepositoryInterface interface {
+	// SelectAuditEvents gets the audit events matching the filters
+	SelectAuditEvents(ctx context.Context, data types.SelectAuditEvents) ([]entity.AuditEvent, error)
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gomora/infrastructures/database"
+	"gomora/infrastructures/database/types"
+	apiError "gomora/internal/errors"
+	"gomora/module/audit/domain/entity"
+	repositoryTypes "gomora/module/audit/infrastructure/repository/types"
+)
+
+// AuditEventCommandRepository handles the audit event command repository logic
+type AuditEventCommandRepository struct {
+	types.DBHandlerInterface
+}
+
+// InsertAuditEvent appends a new audit event
+func (repository *AuditEventCommandRepository) InsertAuditEvent(ctx context.Context, data repositoryTypes.CreateAuditEvent) (entity.AuditEvent, error) {
+	auditEvent := entity.AuditEvent{
+		ID:         data.ID,
+		OccurredAt: data.OccurredAt,
+		Actor:      data.Actor,
+		TenantID:   data.TenantID,
+		IP:         data.IP,
+		RequestID:  data.RequestID,
+		Action:     data.Action,
+		ResourceID: data.ResourceID,
+		Outcome:    data.Outcome,
+		ErrorCode:  data.ErrorCode,
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO %s (id, occurred_at, actor, tenant_id, ip, request_id, action, resource_id, outcome, error_code) VALUES (:id, :occurred_at, :actor, :tenant_id, :ip, :request_id, :action, :resource_id, :outcome, :error_code)", auditEvent.GetModelName())
+	// a retried insert that was applied before the connection was lost would fail as a duplicate
+	_, err := repository.DBHandlerInterface.Execute(database.WithoutRetry(ctx), stmt, auditEvent)
+	if err != nil {
+		return entity.AuditEvent{}, errors.New(apiError.DatabaseError)
+	}
+
+	return auditEvent, nil
+}
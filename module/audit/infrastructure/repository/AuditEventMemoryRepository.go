@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"gomora/module/audit/domain/entity"
+	repositoryTypes "gomora/module/audit/infrastructure/repository/types"
+)
+
+// AuditEventMemoryRepository keeps the audit events in memory, for unit tests and demo mode
+// It implements both the command and the query repository with the semantics of the database ones.
+type AuditEventMemoryRepository struct {
+	mu          sync.RWMutex
+	auditEvents []entity.AuditEvent // sorted by id
+}
+
+// InsertAuditEvent appends a new audit event
+func (repository *AuditEventMemoryRepository) InsertAuditEvent(ctx context.Context, data repositoryTypes.CreateAuditEvent) (entity.AuditEvent, error) {
+	auditEvent := entity.AuditEvent(data)
+
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	i := sort.Search(len(repository.auditEvents), func(i int) bool { return repository.auditEvents[i].ID >= auditEvent.ID })
+	repository.auditEvents = append(repository.auditEvents, entity.AuditEvent{})
+	copy(repository.auditEvents[i+1:], repository.auditEvents[i:])
+	repository.auditEvents[i] = auditEvent
+
+	return auditEvent, nil
+}
+
+// SelectAuditEvents select the audit events matching the filters, from the most recent
+func (repository *AuditEventMemoryRepository) SelectAuditEvents(ctx context.Context, data repositoryTypes.SelectAuditEvents) ([]entity.AuditEvent, error) {
+	auditEvents := []entity.AuditEvent{}
+
+	repository.mu.RLock()
+	defer repository.mu.RUnlock()
+
+	for i := len(repository.auditEvents) - 1; i >= 0 && len(auditEvents) < data.Limit; i-- {
+		auditEvent := repository.auditEvents[i]
+
+		switch {
+		case len(data.Before) > 0 && auditEvent.ID >= data.Before,
+			len(data.Actor) > 0 && auditEvent.Actor != data.Actor,
+			len(data.TenantID) > 0 && auditEvent.TenantID != data.TenantID,
+			len(data.Action) > 0 && auditEvent.Action != data.Action,
+			len(data.ResourceID) > 0 && auditEvent.ResourceID != data.ResourceID,
+			len(data.Outcome) > 0 && auditEvent.Outcome != data.Outcome,
+			data.From != nil && auditEvent.OccurredAt.Before(*data.From),
+			data.To != nil && !auditEvent.OccurredAt.Before(*data.To):
+			continue
+		}
+
+		auditEvents = append(auditEvents, auditEvent)
+	}
+
+	return auditEvents, nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gomora/infrastructures/database/types"
+	apiError "gomora/internal/errors"
+	"gomora/module/audit/domain/entity"
+	repositoryTypes "gomora/module/audit/infrastructure/repository/types"
+)
+
+// AuditEventQueryRepository handles the audit event query repository logic
+type AuditEventQueryRepository struct {
+	types.DBHandlerInterface
+}
+
+// SelectAuditEvents select the audit events matching the filters, from the most recent
+func (repository *AuditEventQueryRepository) SelectAuditEvents(ctx context.Context, data repositoryTypes.SelectAuditEvents) ([]entity.AuditEvent, error) {
+	var auditEvent entity.AuditEvent
+	auditEvents := []entity.AuditEvent{}
+
+	conditions := []string{"1=1"}
+	params := map[string]interface{}{
+		"limit": data.Limit,
+	}
+
+	filters := []struct {
+		column string
+		value  string
+	}{
+		{"actor", data.Actor},
+		{"tenant_id", data.TenantID},
+		{"action", data.Action},
+		{"resource_id", data.ResourceID},
+		{"outcome", data.Outcome},
+	}
+	for _, filter := range filters {
+		if len(filter.value) > 0 {
+			conditions = append(conditions, fmt.Sprintf("%s=:%s", filter.column, filter.column))
+			params[filter.column] = filter.value
+		}
+	}
+
+	if data.From != nil {
+		conditions = append(conditions, "occurred_at>=:from")
+		params["from"] = *data.From
+	}
+	if data.To != nil {
+		conditions = append(conditions, "occurred_at<:to")
+		params["to"] = *data.To
+	}
+	if len(data.Before) > 0 {
+		conditions = append(conditions, "id<:before")
+		params["before"] = data.Before
+	}
+
+	stmt := fmt.Sprintf("SELECT * FROM %s WHERE %s ORDER BY id DESC LIMIT :limit", auditEvent.GetModelName(), strings.Join(conditions, " AND "))
+	err := repository.Query(ctx, stmt, params, &auditEvents)
+	if err != nil {
+		return nil, errors.New(apiError.DatabaseError)
+	}
+
+	return auditEvents, nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/segmentio/ksuid"
+
+	"gomora/infrastructures/database/sqlite"
+	sqliteTypes "gomora/infrastructures/database/sqlite/types"
+	"gomora/module/audit/domain/entity"
+	repositoryTypes "gomora/module/audit/infrastructure/repository/types"
+)
+
+func newSQLiteHandler(t *testing.T) *sqlite.SQLiteDBHandler {
+	t.Helper()
+
+	handler := &sqlite.SQLiteDBHandler{}
+	if err := handler.Connect(sqliteTypes.ConnectionParams{DBPath: sqlite.MemoryPath}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = handler.Close() })
+
+	migrator, err := sqlite.NewMigrator(handler.Conn)
+	if err == nil {
+		err = migrator.Up(context.Background(), 0)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return handler
+}
+
+func TestAuditEventsAppendOnly(t *testing.T) {
+	handler := newSQLiteHandler(t)
+	ctx := context.Background()
+
+	auditEvent, err := (&AuditEventCommandRepository{DBHandlerInterface: handler}).InsertAuditEvent(ctx, repositoryTypes.CreateAuditEvent{
+		ID:         ksuid.New().String(),
+		OccurredAt: time.Now().UTC(),
+		Actor:      "alice",
+		Action:     entity.ActionRecordCreate,
+		Outcome:    entity.OutcomeSuccess,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the table rejects the updates and deletes
+	if _, err := handler.Execute(ctx, "UPDATE audit_events SET outcome='failure' WHERE id=:id", auditEvent); err == nil {
+		t.Error("expected the update to be rejected")
+	}
+	if _, err := handler.Execute(ctx, "DELETE FROM audit_events WHERE id=:id", auditEvent); err == nil {
+		t.Error("expected the delete to be rejected")
+	}
+}
+
+func TestSelectAuditEvents(t *testing.T) {
+	handler := newSQLiteHandler(t)
+	command := &AuditEventCommandRepository{DBHandlerInterface: handler}
+	query := &AuditEventQueryRepository{DBHandlerInterface: handler}
+	ctx := context.Background()
+
+	start := time.Now().UTC().Truncate(time.Second)
+	var ids []string
+	for i := 0; i < 5; i++ {
+		actor := "alice"
+		if i%2 == 1 {
+			actor = "bob"
+		}
+
+		// ksuids sort by time, the events are a second apart
+		id := ksuid.New()
+		id, _ = ksuid.FromParts(start.Add(time.Second*time.Duration(i)), id.Payload())
+		ids = append(ids, id.String())
+
+		_, err := command.InsertAuditEvent(ctx, repositoryTypes.CreateAuditEvent{
+			ID:         id.String(),
+			OccurredAt: start.Add(time.Second * time.Duration(i)),
+			Actor:      actor,
+			TenantID:   "t1",
+			Action:     entity.ActionRecordCreate,
+			ResourceID: fmt.Sprintf("record-%d", i),
+			Outcome:    entity.OutcomeSuccess,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the pages follow each other from the most recent, without gaps nor repeats
+	var paged []string
+	filter := repositoryTypes.SelectAuditEvents{Limit: 2}
+	for {
+		auditEvents, err := query.SelectAuditEvents(ctx, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, auditEvent := range auditEvents {
+			paged = append(paged, auditEvent.ID)
+		}
+		if len(auditEvents) < filter.Limit {
+			break
+		}
+		filter.Before = auditEvents[len(auditEvents)-1].ID
+	}
+	if len(paged) != len(ids) {
+		t.Fatalf("expected %d events, got %d", len(ids), len(paged))
+	}
+	for i, id := range paged {
+		if id != ids[len(ids)-1-i] {
+			t.Errorf("expected %s at %d, got %s", ids[len(ids)-1-i], i, id)
+		}
+	}
+
+	from, to := start.Add(time.Second), start.Add(time.Second*4)
+	auditEvents, err := query.SelectAuditEvents(ctx, repositoryTypes.SelectAuditEvents{Actor: "alice", From: &from, To: &to, Limit: 10})
+	if err != nil || len(auditEvents) != 1 || auditEvents[0].ResourceID != "record-2" {
+		t.Errorf("expected record-2, got %+v, %v", auditEvents, err)
+	}
+
+	auditEvents, err = query.SelectAuditEvents(ctx, repositoryTypes.SelectAuditEvents{TenantID: "t2", Limit: 10})
+	if err != nil || len(auditEvents) != 0 {
+		t.Errorf("expected no events of t2, got %+v, %v", auditEvents, err)
+	}
+}
@@ -0,0 +1,33 @@
+package types
+
+import (
+	"time"
+)
+
+// CreateAuditEvent data struct for create audit event repository
+type CreateAuditEvent struct {
+	ID         string
+	OccurredAt time.Time
+	Actor      string
+	TenantID   string
+	IP         string
+	RequestID  string
+	Action     string
+	ResourceID string
+	Outcome    string
+	ErrorCode  string
+}
+
+// SelectAuditEvents data struct for select audit events repository
+// Empty fields are not filtered on, events are returned from the most recent.
+type SelectAuditEvents struct {
+	Actor      string
+	TenantID   string
+	Action     string
+	ResourceID string
+	Outcome    string
+	From       *time.Time
+	To         *time.Time
+	Before     string // id of the last event of the previous page
+	Limit      int
+}
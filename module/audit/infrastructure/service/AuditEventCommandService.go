@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/segmentio/ksuid"
+
+	ratelimitTypes "gomora/infrastructures/ratelimit/types"
+	"gomora/internal/auth"
+	"gomora/internal/requestinfo"
+	"gomora/internal/tenant"
+	"gomora/module/audit/domain/entity"
+	"gomora/module/audit/domain/repository"
+	repositoryTypes "gomora/module/audit/infrastructure/repository/types"
+	"gomora/module/audit/infrastructure/service/types"
+)
+
+// skippedLogInterval spaces the logs of the rejected authentications not recorded
+const skippedLogInterval = time.Minute
+
+var (
+	skippedAuthFailures    atomic.Int64
+	lastSkippedAuthFailure atomic.Int64
+)
+
+// AuditEventCommandService handles the audit event command service logic
+// The rejected authentications are recorded up to the limits of every client ip and of all of them, so that the
+// unauthenticated traffic can't fill the table. The events over the limits are counted in the logs instead.
+type AuditEventCommandService struct {
+	repository.AuditEventCommandRepositoryInterface
+	AuthFailureStore ratelimitTypes.RateLimitStoreInterface // limits the rejected authentications, skipped when nil
+}
+
+// RecordAuditEvent appends an audit event for the caller of the context
+// Failures are logged, callers are not expected to fail their own action because of them.
+func (service *AuditEventCommandService) RecordAuditEvent(ctx context.Context, data types.RecordAuditEvent) error {
+	identity, _ := auth.FromContext(ctx)
+	info := requestinfo.FromContext(ctx)
+
+	// the admin routes don't resolve any tenant, the events belong to the tenant of the caller
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		tenantID = identity.TenantID
+	}
+
+	if data.Action == entity.ActionAuthFailure && !service.allowAuthFailure(ctx, info.IP) {
+		return nil
+	}
+
+	auditEvent := repositoryTypes.CreateAuditEvent{
+		ID:         ksuid.New().String(),
+		OccurredAt: time.Now().UTC(),
+		Actor:      identity.Subject,
+		TenantID:   tenantID,
+		IP:         info.IP,
+		RequestID:  info.RequestID,
+		Action:     data.Action,
+		ResourceID: data.ResourceID,
+		Outcome:    data.Outcome,
+		ErrorCode:  data.ErrorCode,
+	}
+
+	_, err := service.AuditEventCommandRepositoryInterface.InsertAuditEvent(ctx, auditEvent)
+	if err != nil {
+		log.Printf("[AUDIT] failed to record %s event of %q: %v", auditEvent.Action, auditEvent.Actor, err)
+		return err
+	}
+
+	return nil
+}
+
+// allowAuthFailure returns true when the rejected authentication of the ip is within the limits
+func (service *AuditEventCommandService) allowAuthFailure(ctx context.Context, ip string) bool {
+	if service.AuthFailureStore == nil {
+		return true
+	}
+
+	if result, err := service.AuthFailureStore.Take(ctx, "ip:"+ip, types.AuthFailureClientLimit); err != nil || result.Allowed {
+		if result, err := service.AuthFailureStore.Take(ctx, "all", types.AuthFailureTotalLimit); err != nil || result.Allowed {
+			return true
+		}
+	}
+
+	skipped := skippedAuthFailures.Add(1)
+	if now := time.Now().UnixNano(); now-lastSkippedAuthFailure.Load() > int64(skippedLogInterval) {
+		lastSkippedAuthFailure.Store(now)
+		log.Printf("[AUDIT] %d rejected authentications over the limits not recorded, the last from %s", skipped, ip)
+	}
+
+	return false
+}
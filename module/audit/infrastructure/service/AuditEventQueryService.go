@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"gomora/internal/auth"
+	apiError "gomora/internal/errors"
+	"gomora/module/audit/domain/entity"
+	"gomora/module/audit/domain/repository"
+	repositoryTypes "gomora/module/audit/infrastructure/repository/types"
+	"gomora/module/audit/infrastructure/service/types"
+)
+
+// AuditEventQueryService handles the audit event query service logic
+type AuditEventQueryService struct {
+	repository.AuditEventQueryRepositoryInterface
+}
+
+// GetAuditEvents retrieves a page of the audit events matching the filters
+// The callers bound to a tenant only read the events of their tenant.
+func (service *AuditEventQueryService) GetAuditEvents(ctx context.Context, data types.GetAuditEvents) ([]entity.AuditEvent, error) {
+	if err := scopeToTenant(ctx, &data); err != nil {
+		return nil, err
+	}
+
+	filter := toSelectAuditEvents(data)
+
+	if filter.Limit <= 0 {
+		filter.Limit = types.DefaultLimit
+	}
+	if filter.Limit > types.MaxLimit {
+		filter.Limit = types.MaxLimit
+	}
+
+	return service.AuditEventQueryRepositoryInterface.SelectAuditEvents(ctx, filter)
+}
+
+// ExportAuditEvents passes every audit event matching the filters to the callback, page by page
+// The callers bound to a tenant only read the events of their tenant.
+func (service *AuditEventQueryService) ExportAuditEvents(ctx context.Context, data types.GetAuditEvents, fn func(entity.AuditEvent) error) error {
+	if err := scopeToTenant(ctx, &data); err != nil {
+		return err
+	}
+
+	filter := toSelectAuditEvents(data)
+	filter.Limit = types.MaxLimit
+
+	for {
+		auditEvents, err := service.AuditEventQueryRepositoryInterface.SelectAuditEvents(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		for _, auditEvent := range auditEvents {
+			if err := fn(auditEvent); err != nil {
+				return err
+			}
+		}
+
+		if len(auditEvents) < filter.Limit {
+			return nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		filter.Before = auditEvents[len(auditEvents)-1].ID
+	}
+}
+
+// scopeToTenant filters the events on the tenant of the caller bound to one, other tenants are forbidden
+func scopeToTenant(ctx context.Context, data *types.GetAuditEvents) error {
+	identity, _ := auth.FromContext(ctx)
+	if len(identity.TenantID) == 0 {
+		return nil
+	}
+
+	if len(data.TenantID) > 0 && data.TenantID != identity.TenantID {
+		return errors.New(apiError.ForbiddenAccess)
+	}
+	data.TenantID = identity.TenantID
+
+	return nil
+}
+
+func toSelectAuditEvents(data types.GetAuditEvents) repositoryTypes.SelectAuditEvents {
+	return repositoryTypes.SelectAuditEvents{
+		Actor:      data.Actor,
+		TenantID:   data.TenantID,
+		Action:     data.Action,
+		ResourceID: data.ResourceID,
+		Outcome:    data.Outcome,
+		From:       data.From,
+		To:         data.To,
+		Before:     data.Before,
+		Limit:      data.Limit,
+	}
+}
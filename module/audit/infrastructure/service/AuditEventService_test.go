@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"gomora/infrastructures/ratelimit"
+	"gomora/internal/auth"
+	apiError "gomora/internal/errors"
+	"gomora/internal/requestinfo"
+	"gomora/internal/tenant"
+	"gomora/module/audit/domain/entity"
+	repositoryTypes "gomora/module/audit/infrastructure/repository/types"
+	"gomora/module/audit/infrastructure/service/types"
+)
+
+// fakeAuditEventRepository keeps the audit events in memory
+type fakeAuditEventRepository struct {
+	auditEvents []entity.AuditEvent
+	selects     int
+}
+
+func (repository *fakeAuditEventRepository) InsertAuditEvent(ctx context.Context, data repositoryTypes.CreateAuditEvent) (entity.AuditEvent, error) {
+	auditEvent := entity.AuditEvent(data)
+	repository.auditEvents = append(repository.auditEvents, auditEvent)
+
+	return auditEvent, nil
+}
+
+func (repository *fakeAuditEventRepository) SelectAuditEvents(ctx context.Context, data repositoryTypes.SelectAuditEvents) ([]entity.AuditEvent, error) {
+	repository.selects++
+
+	auditEvents := []entity.AuditEvent{}
+	for _, auditEvent := range repository.auditEvents {
+		if len(data.TenantID) > 0 && auditEvent.TenantID != data.TenantID {
+			continue
+		}
+		if len(data.Before) > 0 && auditEvent.ID >= data.Before {
+			continue
+		}
+		auditEvents = append(auditEvents, auditEvent)
+	}
+
+	sort.Slice(auditEvents, func(i, j int) bool { return auditEvents[i].ID > auditEvents[j].ID })
+	if len(auditEvents) > data.Limit {
+		auditEvents = auditEvents[:data.Limit]
+	}
+
+	return auditEvents, nil
+}
+
+func TestRecordAuditEvent(t *testing.T) {
+	repository := &fakeAuditEventRepository{}
+	service := &AuditEventCommandService{AuditEventCommandRepositoryInterface: repository}
+
+	ctx := auth.NewContext(context.Background(), auth.Identity{Subject: "alice", TenantID: "t1"})
+	ctx = requestinfo.NewContext(ctx, requestinfo.Info{RequestID: "req-1", IP: "10.0.0.1"})
+
+	err := service.RecordAuditEvent(ctx, types.NewRecordAuditEvent(entity.ActionRecordCreate, "record-1", errors.New(apiError.DatabaseError)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the admin routes carry no tenant, the event falls back to the tenant of the caller
+	err = service.RecordAuditEvent(tenant.NewContext(ctx, "t2"), types.NewRecordAuditEvent(entity.ActionRecordCreate, "record-2", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(repository.auditEvents) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(repository.auditEvents))
+	}
+
+	auditEvent := repository.auditEvents[0]
+	if len(auditEvent.ID) == 0 || auditEvent.OccurredAt.IsZero() {
+		t.Errorf("expected an id and a time, got %+v", auditEvent)
+	}
+	expected := entity.AuditEvent{
+		ID:         auditEvent.ID,
+		OccurredAt: auditEvent.OccurredAt,
+		Actor:      "alice",
+		TenantID:   "t1",
+		IP:         "10.0.0.1",
+		RequestID:  "req-1",
+		Action:     entity.ActionRecordCreate,
+		ResourceID: "record-1",
+		Outcome:    entity.OutcomeFailure,
+		ErrorCode:  apiError.DatabaseError,
+	}
+	if auditEvent != expected {
+		t.Errorf("expected %+v, got %+v", expected, auditEvent)
+	}
+
+	if auditEvent := repository.auditEvents[1]; auditEvent.TenantID != "t2" || auditEvent.Outcome != entity.OutcomeSuccess || len(auditEvent.ErrorCode) > 0 {
+		t.Errorf("expected a success of t2, got %+v", auditEvent)
+	}
+}
+
+func TestRecordAuthFailures(t *testing.T) {
+	repository := &fakeAuditEventRepository{}
+	service := &AuditEventCommandService{
+		AuditEventCommandRepositoryInterface: repository,
+		AuthFailureStore:                     ratelimit.NewMemoryStore(),
+	}
+
+	record := func(ip string, action string) {
+		ctx := requestinfo.NewContext(context.Background(), requestinfo.Info{IP: ip})
+		if err := service.RecordAuditEvent(ctx, types.NewRecordAuditEvent(action, "", nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 3*types.AuthFailureClientLimit.Burst; i++ {
+		record("10.0.0.1", entity.ActionAuthFailure)
+	}
+	if len(repository.auditEvents) != types.AuthFailureClientLimit.Burst {
+		t.Errorf("expected the burst of %d failures of the ip, got %d", types.AuthFailureClientLimit.Burst, len(repository.auditEvents))
+	}
+
+	// the other ips and the other actions are still recorded
+	record("10.0.0.2", entity.ActionAuthFailure)
+	record("10.0.0.1", entity.ActionRecordCreate)
+	if len(repository.auditEvents) != types.AuthFailureClientLimit.Burst+2 {
+		t.Errorf("expected %d events, got %d", types.AuthFailureClientLimit.Burst+2, len(repository.auditEvents))
+	}
+
+	// the failures of all the ips are limited together
+	for i := 0; i < 2*types.AuthFailureTotalLimit.Burst; i++ {
+		record(fmt.Sprintf("10.1.%d.%d", i/256, i%256), entity.ActionAuthFailure)
+	}
+	failures := 0
+	for _, auditEvent := range repository.auditEvents {
+		if auditEvent.Action == entity.ActionAuthFailure {
+			failures++
+		}
+	}
+	if failures > types.AuthFailureTotalLimit.Burst+types.AuthFailureClientLimit.Burst {
+		t.Errorf("expected at most the total burst of failures, got %d", failures)
+	}
+}
+
+func TestExportAuditEvents(t *testing.T) {
+	repository := &fakeAuditEventRepository{}
+	total := 2*types.MaxLimit + 3
+	for i := 0; i < total; i++ {
+		repository.auditEvents = append(repository.auditEvents, entity.AuditEvent{ID: fmt.Sprintf("%06d", i), TenantID: "t1"})
+	}
+	service := &AuditEventQueryService{AuditEventQueryRepositoryInterface: repository}
+
+	var exported []string
+	err := service.ExportAuditEvents(context.Background(), types.GetAuditEvents{}, func(auditEvent entity.AuditEvent) error {
+		exported = append(exported, auditEvent.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(exported) != total {
+		t.Fatalf("expected %d events, got %d", total, len(exported))
+	}
+	for i, id := range exported {
+		if expected := fmt.Sprintf("%06d", total-1-i); id != expected {
+			t.Fatalf("expected %s at %d, got %s", expected, i, id)
+		}
+	}
+	if repository.selects != 3 {
+		t.Errorf("expected 3 pages, got %d", repository.selects)
+	}
+
+	// the export stops at the first error of the callback
+	stop := errors.New("stop")
+	err = service.ExportAuditEvents(context.Background(), types.GetAuditEvents{}, func(auditEvent entity.AuditEvent) error {
+		return stop
+	})
+	if err != stop {
+		t.Errorf("expected the callback error, got %v", err)
+	}
+}
+
+func TestGetAuditEventsTenant(t *testing.T) {
+	repository := &fakeAuditEventRepository{auditEvents: []entity.AuditEvent{
+		{ID: "1", TenantID: "t1"},
+		{ID: "2", TenantID: "t2"},
+	}}
+	service := &AuditEventQueryService{AuditEventQueryRepositoryInterface: repository}
+
+	tests := []struct {
+		name     string
+		identity auth.Identity
+		tenantID string
+		expected []string
+		err      string
+	}{
+		{name: "unbound caller reads every tenant", identity: auth.Identity{Subject: "root"}, expected: []string{"2", "1"}},
+		{name: "unbound caller filters on a tenant", identity: auth.Identity{Subject: "root"}, tenantID: "t2", expected: []string{"2"}},
+		{name: "bound caller reads its tenant", identity: auth.Identity{Subject: "alice", TenantID: "t1"}, expected: []string{"1"}},
+		{name: "bound caller filters on its tenant", identity: auth.Identity{Subject: "alice", TenantID: "t1"}, tenantID: "t1", expected: []string{"1"}},
+		{name: "bound caller is forbidden other tenants", identity: auth.Identity{Subject: "alice", TenantID: "t1"}, tenantID: "t2", err: apiError.ForbiddenAccess},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := auth.NewContext(context.Background(), test.identity)
+			data := types.GetAuditEvents{TenantID: test.tenantID}
+
+			auditEvents, err := service.GetAuditEvents(ctx, data)
+			if len(test.err) > 0 {
+				if err == nil || err.Error() != test.err {
+					t.Fatalf("expected %s, got %v", test.err, err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []string
+			for _, auditEvent := range auditEvents {
+				ids = append(ids, auditEvent.ID)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, ids)
+			}
+
+			// the export is scoped the same way
+			var exported []string
+			err = service.ExportAuditEvents(ctx, data, func(auditEvent entity.AuditEvent) error {
+				exported = append(exported, auditEvent.ID)
+				return nil
+			})
+			if len(test.err) > 0 {
+				if err == nil || err.Error() != test.err {
+					t.Fatalf("expected %s, got %v", test.err, err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(exported) != fmt.Sprint(test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, exported)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package types
+
+import (
+	"time"
+
+	ratelimitTypes "gomora/infrastructures/ratelimit/types"
+	"gomora/module/audit/domain/entity"
+)
+
+const (
+	// DefaultLimit is the page size of audit events when none is requested
+	DefaultLimit int = 100
+	// MaxLimit is the largest page size of audit events
+	MaxLimit int = 1000
+)
+
+var (
+	// AuthFailureClientLimit is the rate of rejected authentications recorded per client ip
+	AuthFailureClientLimit = ratelimitTypes.Limit{Rate: 1, Burst: 10}
+	// AuthFailureTotalLimit is the rate of rejected authentications recorded for all the clients
+	AuthFailureTotalLimit = ratelimitTypes.Limit{Rate: 50, Burst: 200}
+)
+
+// RecordAuditEvent service types for record audit event
+// The actor, tenant, ip and request id are taken from the context.
+type RecordAuditEvent struct {
+	Action     string
+	ResourceID string
+	Outcome    string
+	ErrorCode  string
+}
+
+// NewRecordAuditEvent returns the audit event of the action, failed with the error code when err is not nil
+func NewRecordAuditEvent(action string, resourceID string, err error) RecordAuditEvent {
+	event := RecordAuditEvent{
+		Action:     action,
+		ResourceID: resourceID,
+		Outcome:    entity.OutcomeSuccess,
+	}
+
+	if err != nil {
+		event.Outcome = entity.OutcomeFailure
+		event.ErrorCode = err.Error()
+	}
+
+	return event
+}
+
+// GetAuditEvents service types for get audit events
+type GetAuditEvents struct {
+	Actor      string
+	TenantID   string
+	Action     string
+	ResourceID string
+	Outcome    string
+	From       *time.Time
+	To         *time.Time
+	Before     string
+	Limit      int
+}
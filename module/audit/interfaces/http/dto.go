@@ -0,0 +1,22 @@
+package http
+
+// AuditEventResponse response struct
+type AuditEventResponse struct {
+	ID         string `json:"id"`
+	OccurredAt int64  `json:"occurredAt"`
+	Actor      string `json:"actor"`
+	TenantID   string `json:"tenantId"`
+	IP         string `json:"ip"`
+	RequestID  string `json:"requestId"`
+	Action     string `json:"action"`
+	ResourceID string `json:"resourceId"`
+	Outcome    string `json:"outcome"`
+	ErrorCode  string `json:"errorCode,omitempty"`
+}
+
+// GetAuditEventsResponse response struct
+// Next is the cursor of the following page, empty on the last page.
+type GetAuditEventsResponse struct {
+	Events []AuditEventResponse `json:"events"`
+	Next   string               `json:"next"`
+}
@@ -0,0 +1,222 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gomora/interfaces/http/rest/viewmodels"
+	"gomora/internal/errors"
+	"gomora/module/audit/application"
+	"gomora/module/audit/domain/entity"
+	serviceTypes "gomora/module/audit/infrastructure/service/types"
+	types "gomora/module/audit/interfaces/http"
+)
+
+// AuditEventQueryController request controller for audit event query
+type AuditEventQueryController struct {
+	application.AuditEventQueryServiceInterface
+}
+
+// GetAuditEvents retrieves a page of the audit events matching the query filters
+func (controller *AuditEventQueryController) GetAuditEvents(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parseFilter(r)
+	if !ok {
+		response := viewmodels.HTTPResponseVM{
+			Status:    http.StatusBadRequest,
+			Success:   false,
+			Message:   "Invalid filters.",
+			ErrorCode: errors.InvalidRequestPayload,
+		}
+
+		response.JSON(w)
+		return
+	}
+
+	res, err := controller.AuditEventQueryServiceInterface.GetAuditEvents(r.Context(), filter)
+	if err != nil {
+		var httpCode int
+		var errorMsg string
+
+		switch err.Error() {
+		case errors.DatabaseError:
+			httpCode = http.StatusInternalServerError
+			errorMsg = "Error while fetching audit events."
+		case errors.ForbiddenAccess:
+			httpCode = http.StatusForbidden
+			errorMsg = "Audit events of other tenants are forbidden."
+		default:
+			httpCode = http.StatusInternalServerError
+			errorMsg = "Please contact technical support."
+		}
+
+		response := viewmodels.HTTPResponseVM{
+			Status:    httpCode,
+			Success:   false,
+			Message:   errorMsg,
+			ErrorCode: err.Error(),
+		}
+
+		response.JSON(w)
+		return
+	}
+
+	events := &types.GetAuditEventsResponse{
+		Events: []types.AuditEventResponse{},
+	}
+	for _, auditEvent := range res {
+		events.Events = append(events.Events, toAuditEventResponse(auditEvent))
+	}
+
+	// a full page may be followed by another one
+	if len(res) > 0 && len(res) == filter.Limit {
+		events.Next = res[len(res)-1].ID
+	}
+
+	response := viewmodels.HTTPResponseVM{
+		Status:  http.StatusOK,
+		Success: true,
+		Message: "Audit events successfully fetched.",
+		Data:    events,
+	}
+
+	response.JSON(w)
+}
+
+// ExportAuditEvents streams every audit event matching the query filters as newline delimited json
+func (controller *AuditEventQueryController) ExportAuditEvents(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parseFilter(r)
+	if !ok {
+		response := viewmodels.HTTPResponseVM{
+			Status:    http.StatusBadRequest,
+			Success:   false,
+			Message:   "Invalid filters.",
+			ErrorCode: errors.InvalidRequestPayload,
+		}
+
+		response.JSON(w)
+		return
+	}
+
+	encoder := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+
+	var streaming bool
+	startStream := func() {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", `attachment; filename="audit-events.ndjson"`)
+		streaming = true
+	}
+
+	// the status is already sent once the first event is written, failures can only cut the stream short
+	err := controller.AuditEventQueryServiceInterface.ExportAuditEvents(r.Context(), filter, func(auditEvent entity.AuditEvent) error {
+		if !streaming {
+			startStream()
+		}
+
+		if err := encoder.Encode(toAuditEventResponse(auditEvent)); err != nil {
+			return err
+		}
+
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		return nil
+	})
+	if err != nil && streaming {
+		panic(http.ErrAbortHandler)
+	}
+
+	if err != nil {
+		var httpCode int
+		var errorMsg string
+
+		switch err.Error() {
+		case errors.ForbiddenAccess:
+			httpCode = http.StatusForbidden
+			errorMsg = "Audit events of other tenants are forbidden."
+		default:
+			httpCode = http.StatusInternalServerError
+			errorMsg = "Error while exporting audit events."
+		}
+
+		response := viewmodels.HTTPResponseVM{
+			Status:    httpCode,
+			Success:   false,
+			Message:   errorMsg,
+			ErrorCode: err.Error(),
+		}
+
+		response.JSON(w)
+		return
+	}
+
+	// nothing matched, the export is empty
+	if !streaming {
+		startStream()
+	}
+}
+
+// parseFilter reads the audit event filters from the query string
+func parseFilter(r *http.Request) (serviceTypes.GetAuditEvents, bool) {
+	query := r.URL.Query()
+
+	filter := serviceTypes.GetAuditEvents{
+		Actor:      query.Get("actor"),
+		TenantID:   query.Get("tenantId"),
+		Action:     query.Get("action"),
+		ResourceID: query.Get("resourceId"),
+		Outcome:    query.Get("outcome"),
+		Before:     query.Get("before"),
+	}
+
+	for key, target := range map[string]**time.Time{
+		"from": &filter.From,
+		"to":   &filter.To,
+	} {
+		if value := query.Get(key); len(value) > 0 {
+			unix, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return filter, false
+			}
+
+			t := time.Unix(unix, 0).UTC()
+			*target = &t
+		}
+	}
+
+	if value := query.Get("limit"); len(value) > 0 {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return filter, false
+		}
+
+		filter.Limit = limit
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = serviceTypes.DefaultLimit
+	}
+	if filter.Limit > serviceTypes.MaxLimit {
+		filter.Limit = serviceTypes.MaxLimit
+	}
+
+	return filter, true
+}
+
+func toAuditEventResponse(auditEvent entity.AuditEvent) types.AuditEventResponse {
+	return types.AuditEventResponse{
+		ID:         auditEvent.ID,
+		OccurredAt: auditEvent.OccurredAt.Unix(),
+		Actor:      auditEvent.Actor,
+		TenantID:   auditEvent.TenantID,
+		IP:         auditEvent.IP,
+		RequestID:  auditEvent.RequestID,
+		Action:     auditEvent.Action,
+		ResourceID: auditEvent.ResourceID,
+		Outcome:    auditEvent.Outcome,
+		ErrorCode:  auditEvent.ErrorCode,
+	}
+}
@@ -0,0 +1,15 @@
+package application
+
+import (
+	"context"
+
+	"gomora/module/circuit/domain/entity"
+)
+
+// CircuitCommandServiceInterface holds the implementable methods for the circuit command service
+type CircuitCommandServiceInterface interface {
+	// ForceOpenCircuit opens the circuit until it is reset
+	ForceOpenCircuit(ctx context.Context, name string) (entity.Circuit, error)
+	// ResetCircuit closes the circuit and clears its metrics
+	ResetCircuit(ctx context.Context, name string) (entity.Circuit, error)
+}
@@ -0,0 +1,13 @@
+package application
+
+import (
+	"context"
+
+	"gomora/module/circuit/domain/entity"
+)
+
+// CircuitQueryServiceInterface holds the implementable methods for the circuit query service
+type CircuitQueryServiceInterface interface {
+	// GetCircuits gets the circuit of every command
+	GetCircuits(ctx context.Context) ([]entity.Circuit, error)
+}
@@ -0,0 +1,10 @@
+package entity
+
+// Circuit holds the state and the rolling metrics of a circuit breaker command
+type Circuit struct {
+	Name            string
+	State           string // closed, open or half-open
+	ForcedOpen      bool
+	ErrorPercentage int
+	RequestVolume   int
+}
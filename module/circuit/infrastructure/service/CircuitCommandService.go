@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"gomora/infrastructures/resilience"
+	apiError "gomora/internal/errors"
+	auditApplication "gomora/module/audit/application"
+	auditEntity "gomora/module/audit/domain/entity"
+	auditTypes "gomora/module/audit/infrastructure/service/types"
+	"gomora/module/circuit/domain/entity"
+)
+
+// CircuitCommandService handles the circuit command service logic
+type CircuitCommandService struct {
+	auditApplication.AuditEventCommandServiceInterface
+}
+
+// ForceOpenCircuit opens the circuit until it is reset
+func (service *CircuitCommandService) ForceOpenCircuit(ctx context.Context, name string) (entity.Circuit, error) {
+	err := circuitError(resilience.ForceOpen(name))
+	_ = service.AuditEventCommandServiceInterface.RecordAuditEvent(ctx, auditTypes.NewRecordAuditEvent(auditEntity.ActionCircuitForceOpen, name, err))
+	if err != nil {
+		return entity.Circuit{}, err
+	}
+
+	return getCircuit(name)
+}
+
+// ResetCircuit closes the circuit and clears its metrics
+func (service *CircuitCommandService) ResetCircuit(ctx context.Context, name string) (entity.Circuit, error) {
+	err := circuitError(resilience.Reset(name))
+	_ = service.AuditEventCommandServiceInterface.RecordAuditEvent(ctx, auditTypes.NewRecordAuditEvent(auditEntity.ActionCircuitReset, name, err))
+	if err != nil {
+		return entity.Circuit{}, err
+	}
+
+	return getCircuit(name)
+}
+
+// getCircuit returns the current circuit of the command
+func getCircuit(name string) (entity.Circuit, error) {
+	status, err := resilience.Circuit(name)
+	if err != nil {
+		return entity.Circuit{}, circuitError(err)
+	}
+
+	return toCircuit(status), nil
+}
+
+// circuitError maps the errors of the resilience package to the api errors
+func circuitError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, resilience.ErrUnknownCircuit):
+		return errors.New(apiError.MissingRecord)
+	default:
+		return errors.New(apiError.ServerError)
+	}
+}
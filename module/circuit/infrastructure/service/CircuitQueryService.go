@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+
+	"gomora/infrastructures/resilience"
+	"gomora/module/circuit/domain/entity"
+)
+
+// CircuitQueryService handles the circuit query service logic
+type CircuitQueryService struct{}
+
+// GetCircuits gets the circuit of every command
+func (service *CircuitQueryService) GetCircuits(ctx context.Context) ([]entity.Circuit, error) {
+	circuits := []entity.Circuit{}
+	for _, status := range resilience.Circuits() {
+		circuits = append(circuits, toCircuit(status))
+	}
+
+	return circuits, nil
+}
+
+func toCircuit(status resilience.CircuitStatus) entity.Circuit {
+	return entity.Circuit{
+		Name:            status.Name,
+		State:           status.State,
+		ForcedOpen:      status.ForcedOpen,
+		ErrorPercentage: status.ErrorPercentage,
+		RequestVolume:   status.RequestVolume,
+	}
+}
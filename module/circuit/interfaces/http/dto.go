@@ -0,0 +1,15 @@
+package http
+
+// CircuitResponse response struct
+type CircuitResponse struct {
+	Name            string `json:"name"`
+	State           string `json:"state"`
+	ForcedOpen      bool   `json:"forcedOpen"`
+	ErrorPercentage int    `json:"errorPercentage"`
+	RequestVolume   int    `json:"requestVolume"`
+}
+
+// GetCircuitsResponse response struct
+type GetCircuitsResponse struct {
+	Circuits []CircuitResponse `json:"circuits"`
+}
@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"gomora/interfaces/http/rest/viewmodels"
+	"gomora/internal/errors"
+	"gomora/module/circuit/application"
+	"gomora/module/circuit/domain/entity"
+)
+
+// CircuitCommandController request controller for circuit command
+type CircuitCommandController struct {
+	application.CircuitCommandServiceInterface
+}
+
+// ForceOpenCircuit request handler to force a circuit open
+func (controller *CircuitCommandController) ForceOpenCircuit(w http.ResponseWriter, r *http.Request) {
+	controller.handle(w, r, controller.CircuitCommandServiceInterface.ForceOpenCircuit, "Successfully opened circuit.")
+}
+
+// ResetCircuit request handler to reset a circuit
+func (controller *CircuitCommandController) ResetCircuit(w http.ResponseWriter, r *http.Request) {
+	controller.handle(w, r, controller.CircuitCommandServiceInterface.ResetCircuit, "Successfully reset circuit.")
+}
+
+// handle applies the action to the circuit of the request and responds with its new state
+func (controller *CircuitCommandController) handle(w http.ResponseWriter, r *http.Request, action func(ctx context.Context, name string) (entity.Circuit, error), message string) {
+	res, err := action(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		var httpCode int
+		var errorMsg string
+
+		switch err.Error() {
+		case errors.MissingRecord:
+			httpCode = http.StatusNotFound
+			errorMsg = "No circuit found."
+		default:
+			httpCode = http.StatusInternalServerError
+			errorMsg = "Please contact technical support."
+		}
+
+		response := viewmodels.HTTPResponseVM{
+			Status:    httpCode,
+			Success:   false,
+			Message:   errorMsg,
+			ErrorCode: err.Error(),
+		}
+
+		response.JSON(w)
+		return
+	}
+
+	circuit := toCircuitResponse(res)
+	response := viewmodels.HTTPResponseVM{
+		Status:  http.StatusOK,
+		Success: true,
+		Message: message,
+		Data:    &circuit,
+	}
+
+	response.JSON(w)
+}
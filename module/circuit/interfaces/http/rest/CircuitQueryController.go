@@ -0,0 +1,57 @@
+package rest
+
+import (
+	"net/http"
+
+	"gomora/interfaces/http/rest/viewmodels"
+	"gomora/module/circuit/application"
+	"gomora/module/circuit/domain/entity"
+	types "gomora/module/circuit/interfaces/http"
+)
+
+// CircuitQueryController request controller for circuit query
+type CircuitQueryController struct {
+	application.CircuitQueryServiceInterface
+}
+
+// GetCircuits retrieves the state and the metrics of every circuit
+func (controller *CircuitQueryController) GetCircuits(w http.ResponseWriter, r *http.Request) {
+	res, err := controller.CircuitQueryServiceInterface.GetCircuits(r.Context())
+	if err != nil {
+		response := viewmodels.HTTPResponseVM{
+			Status:    http.StatusInternalServerError,
+			Success:   false,
+			Message:   "Please contact technical support.",
+			ErrorCode: err.Error(),
+		}
+
+		response.JSON(w)
+		return
+	}
+
+	circuits := &types.GetCircuitsResponse{
+		Circuits: []types.CircuitResponse{},
+	}
+	for _, circuit := range res {
+		circuits.Circuits = append(circuits.Circuits, toCircuitResponse(circuit))
+	}
+
+	response := viewmodels.HTTPResponseVM{
+		Status:  http.StatusOK,
+		Success: true,
+		Message: "Circuits successfully fetched.",
+		Data:    circuits,
+	}
+
+	response.JSON(w)
+}
+
+func toCircuitResponse(circuit entity.Circuit) types.CircuitResponse {
+	return types.CircuitResponse{
+		Name:            circuit.Name,
+		State:           circuit.State,
+		ForcedOpen:      circuit.ForcedOpen,
+		ErrorPercentage: circuit.ErrorPercentage,
+		RequestVolume:   circuit.RequestVolume,
+	}
+}
@@ -0,0 +1,14 @@
+package application
+
+import (
+	"context"
+
+	"gomora/module/maintenance/domain/entity"
+	"gomora/module/maintenance/infrastructure/service/types"
+)
+
+// MaintenanceCommandServiceInterface holds the implementable methods for the maintenance command service
+type MaintenanceCommandServiceInterface interface {
+	// SetMaintenance switches the maintenance mode
+	SetMaintenance(ctx context.Context, data types.SetMaintenance) (entity.Maintenance, error)
+}
@@ -0,0 +1,13 @@
+package application
+
+import (
+	"context"
+
+	"gomora/module/maintenance/domain/entity"
+)
+
+// MaintenanceQueryServiceInterface holds the implementable methods for the maintenance query service
+type MaintenanceQueryServiceInterface interface {
+	// GetMaintenance gets the maintenance mode
+	GetMaintenance(ctx context.Context) (entity.Maintenance, error)
+}
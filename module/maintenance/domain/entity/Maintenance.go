@@ -0,0 +1,12 @@
+package entity
+
+import (
+	"time"
+)
+
+// Maintenance holds the maintenance mode of the server
+type Maintenance struct {
+	Mode   string // off, read-only or full
+	Reason string
+	Since  time.Time
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"gomora/infrastructures/maintenance"
+	apiError "gomora/internal/errors"
+	auditApplication "gomora/module/audit/application"
+	auditEntity "gomora/module/audit/domain/entity"
+	auditTypes "gomora/module/audit/infrastructure/service/types"
+	"gomora/module/maintenance/domain/entity"
+	"gomora/module/maintenance/infrastructure/service/types"
+)
+
+// MaintenanceCommandService handles the maintenance command service logic
+type MaintenanceCommandService struct {
+	auditApplication.AuditEventCommandServiceInterface
+}
+
+// SetMaintenance switches the maintenance mode
+func (service *MaintenanceCommandService) SetMaintenance(ctx context.Context, data types.SetMaintenance) (entity.Maintenance, error) {
+	status, err := maintenance.Set(data.Mode, data.Reason)
+	if err != nil {
+		err = errors.New(apiError.InvalidPayload)
+	}
+
+	_ = service.AuditEventCommandServiceInterface.RecordAuditEvent(ctx, auditTypes.NewRecordAuditEvent(auditEntity.ActionMaintenanceSet, data.Mode, err))
+	if err != nil {
+		return entity.Maintenance{}, err
+	}
+
+	return toMaintenance(status), nil
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"gomora/infrastructures/maintenance"
+	"gomora/module/maintenance/domain/entity"
+)
+
+// MaintenanceQueryService handles the maintenance query service logic
+type MaintenanceQueryService struct{}
+
+// GetMaintenance gets the maintenance mode
+func (service *MaintenanceQueryService) GetMaintenance(ctx context.Context) (entity.Maintenance, error) {
+	return toMaintenance(maintenance.Current()), nil
+}
+
+func toMaintenance(status maintenance.Status) entity.Maintenance {
+	return entity.Maintenance{
+		Mode:   status.Mode,
+		Reason: status.Reason,
+		Since:  status.Since,
+	}
+}
@@ -0,0 +1,7 @@
+package types
+
+// SetMaintenance service type for set maintenance
+type SetMaintenance struct {
+	Mode   string
+	Reason string
+}
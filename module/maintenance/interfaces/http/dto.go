@@ -0,0 +1,25 @@
+package http
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	Validate         *validator.Validate = validator.New(validator.WithRequiredStructEnabled())
+	ValidationErrors map[string]string   = map[string]string{
+		"SetMaintenanceRequest.Mode": "Mode must be off, read-only or full.",
+	}
+)
+
+// SetMaintenanceRequest request struct for set maintenance
+type SetMaintenanceRequest struct {
+	Mode   string `json:"mode" validate:"required,oneof=off read-only full"`
+	Reason string `json:"reason"`
+}
+
+// MaintenanceResponse response struct
+type MaintenanceResponse struct {
+	Mode   string `json:"mode"`
+	Reason string `json:"reason"`
+	Since  int64  `json:"since"`
+}
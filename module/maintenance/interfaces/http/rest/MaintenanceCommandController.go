@@ -0,0 +1,101 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+
+	"gomora/interfaces/http/rest/viewmodels"
+	apiError "gomora/internal/errors"
+	"gomora/module/maintenance/application"
+	serviceTypes "gomora/module/maintenance/infrastructure/service/types"
+	types "gomora/module/maintenance/interfaces/http"
+)
+
+// MaintenanceCommandController request controller for maintenance command
+type MaintenanceCommandController struct {
+	application.MaintenanceCommandServiceInterface
+}
+
+// SetMaintenance request handler to switch the maintenance mode
+func (controller *MaintenanceCommandController) SetMaintenance(w http.ResponseWriter, r *http.Request) {
+	var request types.SetMaintenanceRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		response := viewmodels.HTTPResponseVM{
+			Status:    http.StatusBadRequest,
+			Success:   false,
+			Message:   "Invalid payload request.",
+			ErrorCode: apiError.InvalidRequestPayload,
+		}
+
+		response.JSON(w)
+		return
+	}
+
+	// validate request
+	err := types.Validate.Struct(request)
+	if err != nil {
+		errors := err.(validator.ValidationErrors)
+		if len(errors) > 0 {
+			response := viewmodels.HTTPResponseVM{
+				Status:    http.StatusBadRequest,
+				Success:   false,
+				Message:   types.ValidationErrors[errors[0].StructNamespace()],
+				ErrorCode: apiError.InvalidPayload,
+			}
+
+			response.JSON(w)
+			return
+		}
+
+		response := viewmodels.HTTPResponseVM{
+			Status:    http.StatusBadRequest,
+			Success:   false,
+			Message:   "Invalid payload request.",
+			ErrorCode: apiError.InvalidRequestPayload,
+		}
+
+		response.JSON(w)
+		return
+	}
+
+	res, err := controller.MaintenanceCommandServiceInterface.SetMaintenance(r.Context(), serviceTypes.SetMaintenance{
+		Mode:   request.Mode,
+		Reason: request.Reason,
+	})
+	if err != nil {
+		var httpCode int
+		var errorMsg string
+
+		switch err.Error() {
+		case apiError.InvalidPayload:
+			httpCode = http.StatusBadRequest
+			errorMsg = types.ValidationErrors["SetMaintenanceRequest.Mode"]
+		default:
+			httpCode = http.StatusInternalServerError
+			errorMsg = "Please contact technical support."
+		}
+
+		response := viewmodels.HTTPResponseVM{
+			Status:    httpCode,
+			Success:   false,
+			Message:   errorMsg,
+			ErrorCode: err.Error(),
+		}
+
+		response.JSON(w)
+		return
+	}
+
+	maintenance := toMaintenanceResponse(res)
+	response := viewmodels.HTTPResponseVM{
+		Status:  http.StatusOK,
+		Success: true,
+		Message: "Successfully switched maintenance mode.",
+		Data:    &maintenance,
+	}
+
+	response.JSON(w)
+}
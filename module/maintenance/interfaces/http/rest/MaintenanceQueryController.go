@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"net/http"
+
+	"gomora/interfaces/http/rest/viewmodels"
+	"gomora/module/maintenance/application"
+	"gomora/module/maintenance/domain/entity"
+	types "gomora/module/maintenance/interfaces/http"
+)
+
+// MaintenanceQueryController request controller for maintenance query
+type MaintenanceQueryController struct {
+	application.MaintenanceQueryServiceInterface
+}
+
+// GetMaintenance retrieves the maintenance mode
+func (controller *MaintenanceQueryController) GetMaintenance(w http.ResponseWriter, r *http.Request) {
+	res, err := controller.MaintenanceQueryServiceInterface.GetMaintenance(r.Context())
+	if err != nil {
+		response := viewmodels.HTTPResponseVM{
+			Status:    http.StatusInternalServerError,
+			Success:   false,
+			Message:   "Please contact technical support.",
+			ErrorCode: err.Error(),
+		}
+
+		response.JSON(w)
+		return
+	}
+
+	maintenance := toMaintenanceResponse(res)
+	response := viewmodels.HTTPResponseVM{
+		Status:  http.StatusOK,
+		Success: true,
+		Message: "Maintenance mode successfully fetched.",
+		Data:    &maintenance,
+	}
+
+	response.JSON(w)
+}
+
+func toMaintenanceResponse(maintenance entity.Maintenance) types.MaintenanceResponse {
+	return types.MaintenanceResponse{
+		Mode:   maintenance.Mode,
+		Reason: maintenance.Reason,
+		Since:  maintenance.Since.Unix(),
+	}
+}
@@ -11,6 +11,6 @@ import (
 type RecordCommandServiceInterface interface {
 	// CreateRecord creates a new record
 	CreateRecord(ctx context.Context, data types.CreateRecord) (entity.Record, error)
-	// GenerateToken generates a jwt token
-	GenerateToken(ctx context.Context) (string, error)
+	// GenerateToken generates a jwt token for the subject
+	GenerateToken(ctx context.Context, data types.GenerateToken) (string, error)
 }
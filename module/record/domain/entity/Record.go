@@ -6,7 +6,9 @@ import (
 
 // Record holds the record entity fields
 type Record struct {
+	TenantID  string `db:"tenant_id"`
 	ID        string
+	OwnerID   string `db:"owner_id"`
 	Data      string
 	CreatedAt time.Time `db:"created_at"`
 }
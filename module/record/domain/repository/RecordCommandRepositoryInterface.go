@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+
 	"gomora/module/record/domain/entity"
 	"gomora/module/record/infrastructure/repository/types"
 )
@@ -8,5 +10,5 @@ import (
 // RecordCommandRepositoryInterface holds the implementable methods for record command repository
 type RecordCommandRepositoryInterface interface {
 	// InsertRecord creates a new record
-	InsertRecord(data types.CreateRecord) (entity.Record, error)
+	InsertRecord(ctx context.Context, data types.CreateRecord) (entity.Record, error)
 }
@@ -1,34 +1,52 @@
 package repository
 
 import (
+	"context"
 	"errors"
-	"fmt"
 
-	"github.com/go-sql-driver/mysql"
-
-	"gomora/infrastructures/database/mysql/types"
+	"gomora/infrastructures/database"
+	"gomora/infrastructures/database/query"
+	"gomora/infrastructures/database/types"
+	"gomora/internal/auth"
 	apiError "gomora/internal/errors"
+	"gomora/internal/tenant"
 	"gomora/module/record/domain/entity"
 	repositoryTypes "gomora/module/record/infrastructure/repository/types"
 )
 
 // RecordCommandRepository handles the record command repository logic
 type RecordCommandRepository struct {
-	types.MySQLDBHandlerInterface
+	types.DBHandlerInterface
 }
 
-// InsertRecord creates a new record
-func (repository *RecordCommandRepository) InsertRecord(data repositoryTypes.CreateRecord) (entity.Record, error) {
+// InsertRecord creates a new record owned by the caller within its tenant
+func (repository *RecordCommandRepository) InsertRecord(ctx context.Context, data repositoryTypes.CreateRecord) (entity.Record, error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return entity.Record{}, errors.New(apiError.UnauthorizedAccess)
+	}
+
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return entity.Record{}, errors.New(apiError.ForbiddenAccess)
+	}
+
 	record := entity.Record{
-		ID:   data.ID,
-		Data: data.Data,
+		TenantID: tenantID,
+		ID:       data.ID,
+		OwnerID:  identity.Subject,
+		Data:     data.Data,
+	}
+
+	// created_at is set by the database
+	stmt, err := query.Insert(&record).Omit("created_at").Build()
+	if err != nil {
+		return entity.Record{}, errors.New(apiError.DatabaseError)
 	}
 
-	stmt := fmt.Sprintf("INSERT INTO %s (id, data) VALUES (:id, :data)", record.GetModelName())
-	_, err := repository.MySQLDBHandlerInterface.Execute(stmt, record)
+	_, err = repository.DBHandlerInterface.Execute(ctx, stmt, record)
 	if err != nil {
-		var mysqlErr *mysql.MySQLError
-		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+		if errors.Is(err, database.ErrDuplicateEntry) {
 			return entity.Record{}, errors.New(apiError.DuplicateRecord)
 		}
 		return entity.Record{}, errors.New(apiError.DatabaseError)
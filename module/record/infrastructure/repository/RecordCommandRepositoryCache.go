@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	cacheTypes "gomora/infrastructures/cache/types"
+	"gomora/module/record/domain/entity"
+	"gomora/module/record/domain/repository"
+	repositoryTypes "gomora/module/record/infrastructure/repository/types"
+)
+
+// RecordCommandRepositoryCache invalidates the cached records on write
+// It shares the backend of the RecordQueryRepositoryCache.
+type RecordCommandRepositoryCache struct {
+	repository.RecordCommandRepositoryInterface
+	Backend cacheTypes.CacheBackendInterface
+}
+
+// InsertRecord decorator pattern to insert record
+func (repository *RecordCommandRepositoryCache) InsertRecord(ctx context.Context, data repositoryTypes.CreateRecord) (entity.Record, error) {
+	record, err := repository.RecordCommandRepositoryInterface.InsertRecord(ctx, data)
+	if err != nil {
+		return record, err
+	}
+
+	repository.invalidate(ctx, record)
+
+	return record, nil
+}
+
+// invalidate removes the record from the cache, its next select reads the repository
+func (repository *RecordCommandRepositoryCache) invalidate(ctx context.Context, record entity.Record) {
+	err := repository.Backend.Delete(context.WithoutCancel(ctx), recordCacheKey(record.TenantID, record.ID))
+	if err != nil {
+		log.Printf("[CACHE] failed to invalidate record %s of tenant %s: %v", record.ID, record.TenantID, err)
+	}
+}
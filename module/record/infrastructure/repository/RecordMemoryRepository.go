@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"gomora/internal/auth"
+	apiError "gomora/internal/errors"
+	"gomora/internal/tenant"
+	"gomora/module/record/domain/entity"
+	repositoryTypes "gomora/module/record/infrastructure/repository/types"
+)
+
+// RecordMemoryRepository keeps the records in memory, for unit tests and demo mode
+// It implements both the command and the query repository with the semantics of the database ones.
+type RecordMemoryRepository struct {
+	mu      sync.RWMutex
+	records map[recordKey]entity.Record
+}
+
+// recordKey is the primary key of the records table
+type recordKey struct {
+	tenantID string
+	ID       string
+}
+
+// InsertRecord creates a new record owned by the caller within its tenant
+func (repository *RecordMemoryRepository) InsertRecord(ctx context.Context, data repositoryTypes.CreateRecord) (entity.Record, error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return entity.Record{}, errors.New(apiError.UnauthorizedAccess)
+	}
+
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return entity.Record{}, errors.New(apiError.ForbiddenAccess)
+	}
+
+	record := entity.Record{
+		TenantID:  tenantID,
+		ID:        data.ID,
+		OwnerID:   identity.Subject,
+		Data:      data.Data,
+		CreatedAt: time.Now().Truncate(time.Second),
+	}
+
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	key := recordKey{tenantID, data.ID}
+	if _, ok := repository.records[key]; ok {
+		return entity.Record{}, errors.New(apiError.DuplicateRecord)
+	}
+
+	if repository.records == nil {
+		repository.records = map[recordKey]entity.Record{}
+	}
+	repository.records[key] = record
+
+	return record, nil
+}
+
+// SelectRecordByID select a record by id within the tenant of the caller
+// Records owned by someone else than the caller are reported missing, unless the caller is an admin.
+func (repository *RecordMemoryRepository) SelectRecordByID(ctx context.Context, ID string) (entity.Record, error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return entity.Record{}, errors.New(apiError.UnauthorizedAccess)
+	}
+
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return entity.Record{}, errors.New(apiError.ForbiddenAccess)
+	}
+
+	repository.mu.RLock()
+	defer repository.mu.RUnlock()
+
+	record, ok := repository.records[recordKey{tenantID, ID}]
+	if !ok || (!identity.IsAdmin() && record.OwnerID != identity.Subject) {
+		return entity.Record{}, errors.New(apiError.MissingRecord)
+	}
+
+	return record, nil
+}
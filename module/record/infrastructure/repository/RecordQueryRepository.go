@@ -1,28 +1,49 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
-	"fmt"
 
-	"gomora/infrastructures/database/mysql/types"
+	"gomora/infrastructures/database/query"
+	"gomora/infrastructures/database/types"
+	"gomora/internal/auth"
 	apiError "gomora/internal/errors"
+	"gomora/internal/tenant"
 	"gomora/module/record/domain/entity"
 )
 
 // RecordQueryRepository handles the record query repository logic
 type RecordQueryRepository struct {
-	types.MySQLDBHandlerInterface
+	types.DBHandlerInterface
 }
 
-// SelectRecordByID select a record by id
-func (repository *RecordQueryRepository) SelectRecordByID(ID string) (entity.Record, error) {
+// SelectRecordByID select a record by id within the tenant of the caller
+// Records owned by someone else than the caller are reported missing, unless the caller is an admin.
+func (repository *RecordQueryRepository) SelectRecordByID(ctx context.Context, ID string) (entity.Record, error) {
 	var record entity.Record
 
-	stmt := fmt.Sprintf("SELECT * FROM %s WHERE id=:id", record.GetModelName())
-	err := repository.QueryRow(stmt, map[string]interface{}{
-		"id": ID,
-	}, &record)
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return record, errors.New(apiError.UnauthorizedAccess)
+	}
+
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return record, errors.New(apiError.ForbiddenAccess)
+	}
+
+	builder := query.Select(&record).Where(query.Eq("tenant_id", tenantID), query.Eq("id", ID))
+	if !identity.IsAdmin() {
+		builder.Where(query.Eq("owner_id", identity.Subject))
+	}
+
+	stmt, params, err := builder.Build()
+	if err != nil {
+		return record, errors.New(apiError.DatabaseError)
+	}
+
+	err = repository.QueryRow(ctx, stmt, params, &record)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return record, errors.New(apiError.MissingRecord)
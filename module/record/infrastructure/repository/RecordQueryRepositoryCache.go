@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"gomora/infrastructures/cache"
+	cacheTypes "gomora/infrastructures/cache/types"
+	"gomora/internal/auth"
+	apiError "gomora/internal/errors"
+	"gomora/internal/stale"
+	"gomora/internal/tenant"
+	"gomora/module/record/domain/entity"
+	"gomora/module/record/domain/repository"
+)
+
+// RecordQueryRepositoryCache read-through cache for record query repository
+// The records are cached per tenant and the concurrent misses of a record are coalesced into a single select.
+// The backend failures are ignored, the records are then selected from the repository.
+type RecordQueryRepositoryCache struct {
+	repository.RecordQueryRepositoryInterface
+	Backend cacheTypes.CacheBackendInterface
+
+	group cache.Group[cachedRecord]
+}
+
+// cachedRecord is the result of a select shared by the coalesced callers
+type cachedRecord struct {
+	record entity.Record
+	stale  bool
+}
+
+// recordCacheKey returns the cache key of the record
+func recordCacheKey(tenantID, ID string) string {
+	return "record:" + tenantID + ":" + ID
+}
+
+// SelectRecordByID decorator pattern for select record repository
+func (repository *RecordQueryRepositoryCache) SelectRecordByID(ctx context.Context, ID string) (entity.Record, error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return repository.RecordQueryRepositoryInterface.SelectRecordByID(ctx, ID)
+	}
+
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return repository.RecordQueryRepositoryInterface.SelectRecordByID(ctx, ID)
+	}
+
+	key := recordCacheKey(tenantID, ID)
+	if value, ok, err := repository.Backend.Get(ctx, key); err == nil && ok {
+		var record entity.Record
+		if err := json.Unmarshal(value, &record); err == nil {
+			// the ownership is checked again, the cache is shared by every caller of the tenant
+			if !identity.IsAdmin() && record.OwnerID != identity.Subject {
+				return entity.Record{}, errors.New(apiError.MissingRecord)
+			}
+
+			return record, nil
+		}
+	}
+
+	// the callers are coalesced by identity, the repository reports the records of others missing
+	caller := identity.Subject
+	if identity.IsAdmin() {
+		caller = "admin"
+	}
+
+	result, err := repository.group.Do(ctx, key+":"+caller, func() (cachedRecord, error) {
+		// the select is shared, so it outlives the cancellation of the caller who started it, not its deadline
+		fetchCtx := context.WithoutCancel(ctx)
+		if deadline, ok := ctx.Deadline(); ok {
+			var cancel context.CancelFunc
+			fetchCtx, cancel = context.WithDeadline(fetchCtx, deadline)
+			defer cancel()
+		}
+		fetchCtx, marker := stale.NewContext(fetchCtx)
+
+		record, err := repository.RecordQueryRepositoryInterface.SelectRecordByID(fetchCtx, ID)
+		if err != nil {
+			return cachedRecord{}, err
+		}
+
+		// the stale records served by a fallback are not cached
+		if marker.Stale() {
+			return cachedRecord{record: record, stale: true}, nil
+		}
+
+		if value, err := json.Marshal(record); err == nil {
+			_ = repository.Backend.Set(fetchCtx, key, value)
+		}
+
+		return cachedRecord{record: record}, nil
+	})
+	if err != nil {
+		// the caller gave up waiting on the shared select, or the select ran out of the caller's deadline
+		if (ctx.Err() != nil && errors.Is(err, ctx.Err())) || errors.Is(err, context.DeadlineExceeded) {
+			return entity.Record{}, errors.New(apiError.RequestTimeout)
+		}
+
+		return entity.Record{}, err
+	}
+
+	if result.stale {
+		stale.Mark(ctx)
+	}
+
+	return result.record, nil
+}
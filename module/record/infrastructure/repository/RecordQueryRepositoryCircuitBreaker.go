@@ -1,40 +1,72 @@
 package repository
 
 import (
-	"github.com/afex/hystrix-go/hystrix"
+	"context"
+	"time"
 
+	hystrix_config "gomora/configs/hystrix"
+	"gomora/infrastructures/cache"
+	"gomora/infrastructures/resilience"
+	"gomora/internal/auth"
+	"gomora/internal/stale"
+	"gomora/internal/tenant"
 	"gomora/module/record/domain/entity"
 	"gomora/module/record/domain/repository"
 )
 
+// RecordStaleCache holds the last known records, served when the database is unavailable
+type RecordStaleCache = cache.LRU[recordKey, entity.Record]
+
+// NewRecordStaleCache returns a stale cache of up to size records, kept for maxAge
+func NewRecordStaleCache(size int, maxAge time.Duration) *RecordStaleCache {
+	return cache.NewLRU[recordKey, entity.Record](size, maxAge)
+}
+
 // RecordQueryRepositoryCircuitBreaker holds the implementable methods for record query circuitbreaker
+// With a stale cache, the last known record is served when the circuit is open or the database fails.
 type RecordQueryRepositoryCircuitBreaker struct {
 	repository.RecordQueryRepositoryInterface
+	Stale *RecordStaleCache // optional
 }
 
 // SelectRecordByID decorator pattern for select record repository
-func (repository *RecordQueryRepositoryCircuitBreaker) SelectRecordByID(ID string) (entity.Record, error) {
-	output := make(chan entity.Record, 1)
-	errChan := make(chan error, 1)
-
-	hystrix.ConfigureCommand("select_record_by_id", config.Settings())
-	errors := hystrix.Go("select_record_by_id", func() error {
-		record, err := repository.RecordQueryRepositoryInterface.SelectRecordByID(ID)
-		if err != nil {
-			errChan <- err
-			return nil
+func (repository *RecordQueryRepositoryCircuitBreaker) SelectRecordByID(ctx context.Context, ID string) (entity.Record, error) {
+	if repository.Stale == nil {
+		return resilience.Execute(ctx, hystrix_config.SelectRecordByID, func(ctx context.Context) (entity.Record, error) {
+			return repository.RecordQueryRepositoryInterface.SelectRecordByID(ctx, ID)
+		})
+	}
+
+	return resilience.Execute(ctx, hystrix_config.SelectRecordByID, func(ctx context.Context) (entity.Record, error) {
+		record, err := repository.RecordQueryRepositoryInterface.SelectRecordByID(ctx, ID)
+		if err == nil {
+			repository.Stale.Set(recordKey{record.TenantID, record.ID}, record)
 		}
 
-		output <- record
-		return nil
-	}, nil)
+		return record, err
+	}, resilience.WithFallback(func(ctx context.Context, err error) (entity.Record, error) {
+		return repository.staleRecord(ctx, ID, err)
+	}))
+}
+
+// staleRecord returns the last known record the caller can read, or the error of the database otherwise
+func (repository *RecordQueryRepositoryCircuitBreaker) staleRecord(ctx context.Context, ID string, err error) (entity.Record, error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return entity.Record{}, err
+	}
 
-	select {
-	case out := <-output:
-		return out, nil
-	case err := <-errChan:
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
 		return entity.Record{}, err
-	case err := <-errors:
+	}
+
+	// the ownership is checked again, the cache is shared by every caller of the tenant
+	record, ok := repository.Stale.Get(recordKey{tenantID, ID})
+	if !ok || (!identity.IsAdmin() && record.OwnerID != identity.Subject) {
 		return entity.Record{}, err
 	}
+	stale.Mark(ctx)
+
+	return record, nil
 }
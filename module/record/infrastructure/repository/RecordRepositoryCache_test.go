@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gomora/infrastructures/cache"
+	"gomora/internal/auth"
+	apiError "gomora/internal/errors"
+	"gomora/internal/stale"
+	"gomora/internal/tenant"
+	"gomora/module/record/domain/entity"
+	repositoryTypes "gomora/module/record/infrastructure/repository/types"
+)
+
+// countingRecordQueryRepository counts the selects reaching the repository
+type countingRecordQueryRepository struct {
+	*RecordMemoryRepository
+	selects atomic.Int32
+	delay   time.Duration
+}
+
+func (repository *countingRecordQueryRepository) SelectRecordByID(ctx context.Context, ID string) (entity.Record, error) {
+	repository.selects.Add(1)
+	time.Sleep(repository.delay)
+
+	return repository.RecordMemoryRepository.SelectRecordByID(ctx, ID)
+}
+
+func TestRecordRepositoryCache(t *testing.T) {
+	memory := &RecordMemoryRepository{}
+	counting := &countingRecordQueryRepository{RecordMemoryRepository: memory}
+	backend := cache.NewMemoryBackend(10, time.Minute)
+	query := &RecordQueryRepositoryCache{RecordQueryRepositoryInterface: counting, Backend: backend}
+	command := &RecordCommandRepositoryCache{RecordCommandRepositoryInterface: memory, Backend: backend}
+	as := func(identity auth.Identity) context.Context {
+		return tenant.NewContext(auth.NewContext(context.Background(), identity), "t1")
+	}
+	alice := as(auth.Identity{Subject: "alice"})
+
+	// the misses are not cached, the insert is seen right away
+	if _, err := query.SelectRecordByID(alice, "1"); err == nil || err.Error() != apiError.MissingRecord {
+		t.Fatalf("expected %s, got %v", apiError.MissingRecord, err)
+	}
+	if _, err := command.InsertRecord(alice, repositoryTypes.CreateRecord{ID: "1", Data: "data"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if record, err := query.SelectRecordByID(alice, "1"); err != nil || record.Data != "data" {
+			t.Fatalf("expected the record, got %+v, %v", record, err)
+		}
+	}
+	if selects := counting.selects.Load(); selects != 2 {
+		t.Errorf("expected 2 selects, got %d", selects)
+	}
+
+	// the cached records are only served to the callers allowed to read them
+	if _, err := query.SelectRecordByID(as(auth.Identity{Subject: "bob"}), "1"); err == nil || err.Error() != apiError.MissingRecord {
+		t.Errorf("expected %s, got %v", apiError.MissingRecord, err)
+	}
+	if _, err := query.SelectRecordByID(as(auth.Identity{Subject: "root", Scopes: []string{auth.ScopeAdmin}}), "1"); err != nil {
+		t.Errorf("expected the record for an admin, got %v", err)
+	}
+	if selects := counting.selects.Load(); selects != 2 {
+		t.Errorf("expected the hits to skip the repository, got %d selects", selects)
+	}
+}
+
+func TestRecordRepositoryCacheCoalescing(t *testing.T) {
+	memory := &RecordMemoryRepository{}
+	counting := &countingRecordQueryRepository{RecordMemoryRepository: memory, delay: time.Millisecond * 50}
+	query := &RecordQueryRepositoryCache{RecordQueryRepositoryInterface: counting, Backend: cache.NewMemoryBackend(10, time.Minute)}
+	ctx := tenant.NewContext(auth.NewContext(context.Background(), auth.Identity{Subject: "alice"}), "t1")
+	if _, err := memory.InsertRecord(ctx, repositoryTypes.CreateRecord{ID: "1", Data: "data"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the concurrent misses share a single select
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if record, err := query.SelectRecordByID(ctx, "1"); err != nil || record.Data != "data" {
+				t.Errorf("expected the record, got %+v, %v", record, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if selects := counting.selects.Load(); selects != 1 {
+		t.Errorf("expected a single select, got %d", selects)
+	}
+}
+
+// staleRecordQueryRepository serves the record as a stale fallback would
+type staleRecordQueryRepository struct {
+	record entity.Record
+}
+
+func (repository *staleRecordQueryRepository) SelectRecordByID(ctx context.Context, ID string) (entity.Record, error) {
+	stale.Mark(ctx)
+
+	return repository.record, nil
+}
+
+func TestRecordRepositoryCacheStale(t *testing.T) {
+	backend := cache.NewMemoryBackend(10, time.Minute)
+	query := &RecordQueryRepositoryCache{
+		RecordQueryRepositoryInterface: &staleRecordQueryRepository{record: entity.Record{TenantID: "t1", ID: "1", OwnerID: "alice"}},
+		Backend:                        backend,
+	}
+	ctx, marker := stale.NewContext(tenant.NewContext(auth.NewContext(context.Background(), auth.Identity{Subject: "alice"}), "t1"))
+
+	// the stale records are flagged to the caller, but never cached
+	if _, err := query.SelectRecordByID(ctx, "1"); err != nil || !marker.Stale() {
+		t.Fatalf("expected a stale record, got %v (stale %t)", err, marker.Stale())
+	}
+	if _, ok, _ := backend.Get(ctx, recordCacheKey("t1", "1")); ok {
+		t.Error("expected the stale record not to be cached")
+	}
+}
+
+// blockingRecordQueryRepository blocks the selects until their context is done
+type blockingRecordQueryRepository struct {
+	*RecordMemoryRepository
+	done chan error
+}
+
+func (repository *blockingRecordQueryRepository) SelectRecordByID(ctx context.Context, ID string) (entity.Record, error) {
+	select {
+	case <-ctx.Done():
+		repository.done <- ctx.Err()
+		return entity.Record{}, ctx.Err()
+	case <-time.After(time.Second * 5):
+		repository.done <- nil
+		return repository.RecordMemoryRepository.SelectRecordByID(ctx, ID)
+	}
+}
+
+func TestRecordRepositoryCacheDeadline(t *testing.T) {
+	blocking := &blockingRecordQueryRepository{RecordMemoryRepository: &RecordMemoryRepository{}, done: make(chan error, 1)}
+	query := &RecordQueryRepositoryCache{RecordQueryRepositoryInterface: blocking, Backend: cache.NewMemoryBackend(10, time.Minute)}
+	ctx := tenant.NewContext(auth.NewContext(context.Background(), auth.Identity{Subject: "alice"}), "t1")
+
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond*20)
+	defer cancel()
+
+	if _, err := query.SelectRecordByID(ctx, "1"); err == nil || err.Error() != apiError.RequestTimeout {
+		t.Fatalf("expected %s, got %v", apiError.RequestTimeout, err)
+	}
+
+	// the shared select is bounded by the deadline of the caller who started it
+	select {
+	case err := <-blocking.done:
+		if err != context.DeadlineExceeded {
+			t.Errorf("expected the select to exceed its deadline, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected the select to stop at the deadline of the caller")
+	}
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/afex/hystrix-go/hystrix"
+
+	hystrix_config "gomora/configs/hystrix"
+	"gomora/internal/auth"
+	apiError "gomora/internal/errors"
+	"gomora/internal/stale"
+	"gomora/internal/tenant"
+	"gomora/module/record/domain/entity"
+)
+
+// failingRecordQueryRepository fails every select with the error
+type failingRecordQueryRepository struct {
+	err error
+}
+
+func (repository *failingRecordQueryRepository) SelectRecordByID(ctx context.Context, ID string) (entity.Record, error) {
+	return entity.Record{}, repository.err
+}
+
+func TestRecordRepositoryCircuitBreaker(t *testing.T) {
+	hystrix.ConfigureCommand(hystrix_config.SelectRecordByID, hystrix.CommandConfig{
+		Timeout:                1000,
+		RequestVolumeThreshold: 5,
+		ErrorPercentThreshold:  50,
+		SleepWindow:            60000,
+	})
+	defer hystrix.Flush()
+
+	failing := &failingRecordQueryRepository{err: errors.New(apiError.MissingRecord)}
+	repository := &RecordQueryRepositoryCircuitBreaker{RecordQueryRepositoryInterface: failing}
+
+	// the business errors are returned as is and never open the circuit
+	for i := 0; i < 20; i++ {
+		if _, err := repository.SelectRecordByID(context.Background(), "1"); err == nil || err.Error() != apiError.MissingRecord {
+			t.Fatalf("expected %s, got %v", apiError.MissingRecord, err)
+		}
+	}
+
+	// the database failures open it, then the calls are rejected
+	failing.err = errors.New(apiError.DatabaseError)
+	deadline := time.Now().Add(time.Second * 5)
+	for {
+		_, err := repository.SelectRecordByID(context.Background(), "1")
+		if err != nil && err.Error() == apiError.HystrixTimeout {
+			break
+		}
+		if err == nil || err.Error() != apiError.DatabaseError {
+			t.Fatalf("expected %s, got %v", apiError.DatabaseError, err)
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the circuit never opened")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+}
+
+// flakyRecordQueryRepository serves the record, then fails like a database going down
+type flakyRecordQueryRepository struct {
+	record entity.Record
+	down   bool
+}
+
+func (repository *flakyRecordQueryRepository) SelectRecordByID(ctx context.Context, ID string) (entity.Record, error) {
+	if repository.down {
+		return entity.Record{}, errors.New(apiError.DatabaseError)
+	}
+
+	return repository.record, nil
+}
+
+func TestRecordRepositoryStaleFallback(t *testing.T) {
+	hystrix.Flush()
+	defer hystrix.Flush()
+
+	flaky := &flakyRecordQueryRepository{record: entity.Record{TenantID: "t1", ID: "1", OwnerID: "alice", Data: "data"}}
+	repository := &RecordQueryRepositoryCircuitBreaker{
+		RecordQueryRepositoryInterface: flaky,
+		Stale:                          NewRecordStaleCache(10, time.Minute),
+	}
+	as := func(subject string) (context.Context, *stale.Marker) {
+		ctx := auth.NewContext(context.Background(), auth.Identity{Subject: subject})
+
+		return stale.NewContext(tenant.NewContext(ctx, "t1"))
+	}
+
+	ctx, marker := as("alice")
+	if _, err := repository.SelectRecordByID(ctx, "1"); err != nil || marker.Stale() {
+		t.Fatalf("expected a fresh record, got %v (stale %t)", err, marker.Stale())
+	}
+
+	// the last known record is served while the database is down
+	flaky.down = true
+	ctx, marker = as("alice")
+	record, err := repository.SelectRecordByID(ctx, "1")
+	if err != nil || record.Data != "data" || !marker.Stale() {
+		t.Errorf("expected a stale record, got %+v, %v (stale %t)", record, err, marker.Stale())
+	}
+
+	// but only to the callers allowed to read it
+	ctx, marker = as("bob")
+	if _, err := repository.SelectRecordByID(ctx, "1"); err == nil || err.Error() != apiError.DatabaseError || marker.Stale() {
+		t.Errorf("expected %s, got %v (stale %t)", apiError.DatabaseError, err, marker.Stale())
+	}
+
+	ctx, _ = as("alice")
+	if _, err := repository.SelectRecordByID(ctx, "2"); err == nil || err.Error() != apiError.DatabaseError {
+		t.Errorf("expected %s, got %v", apiError.DatabaseError, err)
+	}
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/joho/godotenv"
+	"github.com/segmentio/ksuid"
+
+	"gomora/infrastructures/database"
+	"gomora/infrastructures/database/mysql"
+	mysqlTypes "gomora/infrastructures/database/mysql/types"
+	"gomora/infrastructures/database/sqlite"
+	sqliteTypes "gomora/infrastructures/database/sqlite/types"
+	"gomora/internal/auth"
+	apiError "gomora/internal/errors"
+	"gomora/internal/tenant"
+	domainRepository "gomora/module/record/domain/repository"
+	repositoryTypes "gomora/module/record/infrastructure/repository/types"
+)
+
+func TestRecordMemoryRepository(t *testing.T) {
+	repository := &RecordMemoryRepository{}
+
+	testRecordRepository(t, repository, repository)
+}
+
+func TestRecordSQLiteRepository(t *testing.T) {
+	handler := &sqlite.SQLiteDBHandler{}
+	if err := handler.Connect(sqliteTypes.ConnectionParams{DBPath: sqlite.MemoryPath}); err != nil {
+		t.Fatal(err)
+	}
+	defer handler.Close()
+
+	migrator, err := sqlite.NewMigrator(handler.Conn)
+	if err == nil {
+		err = migrator.Up(context.Background(), 0)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testRecordRepository(t, &RecordCommandRepository{DBHandlerInterface: handler}, &RecordQueryRepository{DBHandlerInterface: handler})
+}
+
+func TestRecordMySQLRepository(t *testing.T) {
+	_ = godotenv.Load("../../../../.env")
+	if driver := os.Getenv("DB_DRIVER"); len(os.Getenv("DB_HOST")) == 0 || (len(driver) > 0 && driver != "mysql") {
+		t.Skip("no mysql server configured")
+	}
+
+	handler := &mysql.MySQLDBHandler{}
+	err := handler.Connect(mysqlTypes.ConnectionParams{
+		DBHost:     os.Getenv("DB_HOST"),
+		DBPort:     os.Getenv("DB_PORT"),
+		DBDatabase: os.Getenv("DB_DATABASE"),
+		DBUsername: os.Getenv("DB_USERNAME"),
+		DBPassword: os.Getenv("DB_PASSWORD"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer handler.Close()
+
+	migrator, err := mysql.NewMigrator(handler.Conn)
+	if err == nil {
+		err = migrator.Up(context.Background(), 0)
+	}
+	if err != nil && !errors.Is(err, database.ErrNoChange) {
+		t.Fatal(err)
+	}
+
+	testRecordRepository(t, &RecordCommandRepository{DBHandlerInterface: handler}, &RecordQueryRepository{DBHandlerInterface: handler})
+}
+
+// testRecordRepository is the conformance suite every record repository implementation must pass
+func testRecordRepository(t *testing.T, command domainRepository.RecordCommandRepositoryInterface, query domainRepository.RecordQueryRepositoryInterface) {
+	// unique tenants keep the runs against a shared database apart
+	tenantA, tenantB := ksuid.New().String(), ksuid.New().String()
+	as := func(subject string, scopes []string, tenantID string) context.Context {
+		ctx := auth.NewContext(context.Background(), auth.Identity{Subject: subject, Scopes: scopes})
+
+		return tenant.NewContext(ctx, tenantID)
+	}
+	alice, bob := as("alice", nil, tenantA), as("bob", nil, tenantA)
+	admin := as("admin", []string{auth.ScopeAdmin}, tenantA)
+	expectCode := func(name string, err error, code string) {
+		t.Helper()
+		if err == nil || err.Error() != code {
+			t.Errorf("%s: expected %s, got %v", name, code, err)
+		}
+	}
+
+	// the caller must be authenticated and bound to a tenant
+	_, err := command.InsertRecord(context.Background(), repositoryTypes.CreateRecord{ID: "1", Data: "data"})
+	expectCode("insert without identity", err, apiError.UnauthorizedAccess)
+	_, err = query.SelectRecordByID(context.Background(), "1")
+	expectCode("select without identity", err, apiError.UnauthorizedAccess)
+	_, err = command.InsertRecord(auth.NewContext(context.Background(), auth.Identity{Subject: "alice"}), repositoryTypes.CreateRecord{ID: "1", Data: "data"})
+	expectCode("insert without tenant", err, apiError.ForbiddenAccess)
+
+	// insert
+	record, err := command.InsertRecord(alice, repositoryTypes.CreateRecord{ID: "1", Data: "data"})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if record.TenantID != tenantA || record.ID != "1" || record.OwnerID != "alice" || record.Data != "data" {
+		t.Errorf("insert: unexpected record %+v", record)
+	}
+
+	_, err = command.InsertRecord(bob, repositoryTypes.CreateRecord{ID: "1", Data: "other"})
+	expectCode("insert duplicate", err, apiError.DuplicateRecord)
+
+	if _, err := command.InsertRecord(as("alice", nil, tenantB), repositoryTypes.CreateRecord{ID: "1", Data: "tenant b"}); err != nil {
+		t.Errorf("insert in another tenant: %v", err)
+	}
+
+	// select
+	selected, err := query.SelectRecordByID(alice, "1")
+	if err != nil || selected.TenantID != tenantA || selected.ID != "1" || selected.OwnerID != "alice" || selected.Data != "data" {
+		t.Errorf("select by owner: unexpected record %+v (%v)", selected, err)
+	}
+
+	if selected, err := query.SelectRecordByID(admin, "1"); err != nil || selected.Data != "data" {
+		t.Errorf("select by admin: unexpected record %+v (%v)", selected, err)
+	}
+
+	_, err = query.SelectRecordByID(bob, "1")
+	expectCode("select by someone else", err, apiError.MissingRecord)
+	_, err = query.SelectRecordByID(alice, "2")
+	expectCode("select unknown", err, apiError.MissingRecord)
+	_, err = query.SelectRecordByID(as("alice", nil, ksuid.New().String()), "1")
+	expectCode("select from another tenant", err, apiError.MissingRecord)
+
+	// concurrent inserts of the same record, only one of them wins
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := command.InsertRecord(alice, repositoryTypes.CreateRecord{ID: "concurrent", Data: "data"})
+			if err == nil {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			} else if err.Error() != apiError.DuplicateRecord {
+				t.Errorf("concurrent insert: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if created != 1 {
+		t.Errorf("concurrent insert: expected a single record, got %d", created)
+	}
+}
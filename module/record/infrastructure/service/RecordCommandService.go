@@ -2,12 +2,20 @@ package service
 
 import (
 	"context"
+	"errors"
 	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/segmentio/ksuid"
 
+	dbTypes "gomora/infrastructures/database/types"
+	"gomora/internal/auth"
+	apiError "gomora/internal/errors"
+	"gomora/internal/tenant"
+	auditApplication "gomora/module/audit/application"
+	auditEntity "gomora/module/audit/domain/entity"
+	auditTypes "gomora/module/audit/infrastructure/service/types"
 	"gomora/module/record/domain/entity"
 	"gomora/module/record/domain/repository"
 	repositoryTypes "gomora/module/record/infrastructure/repository/types"
@@ -16,10 +24,13 @@ import (
 
 // RecordCommandService handles the record command service logic
 type RecordCommandService struct {
+	dbTypes.TransactionHandlerInterface
 	repository.RecordCommandRepositoryInterface
+	auditApplication.AuditEventCommandServiceInterface
 }
 
 // CreateRecord create a record
+// The record and its audit event are saved in the same transaction.
 func (service *RecordCommandService) CreateRecord(ctx context.Context, data types.CreateRecord) (entity.Record, error) {
 	record := repositoryTypes.CreateRecord{
 		ID:   data.ID,
@@ -31,8 +42,27 @@ func (service *RecordCommandService) CreateRecord(ctx context.Context, data type
 		record.ID = generateID()
 	}
 
-	res, err := service.RecordCommandRepositoryInterface.InsertRecord(record)
+	var res entity.Record
+	var workErr error
+
+	err := service.TransactionHandlerInterface.WithTransaction(ctx, func(ctx context.Context) error {
+		res, workErr = service.RecordCommandRepositoryInterface.InsertRecord(ctx, record)
+		if workErr != nil {
+			return workErr
+		}
+
+		workErr = service.AuditEventCommandServiceInterface.RecordAuditEvent(ctx, auditTypes.NewRecordAuditEvent(auditEntity.ActionRecordCreate, record.ID, nil))
+
+		return workErr
+	})
 	if err != nil {
+		// begin and commit failures are not translated by the repositories
+		if workErr == nil {
+			err = errors.New(apiError.DatabaseError)
+		}
+
+		_ = service.AuditEventCommandServiceInterface.RecordAuditEvent(ctx, auditTypes.NewRecordAuditEvent(auditEntity.ActionRecordCreate, record.ID, err))
+
 		return entity.Record{}, err
 	}
 
@@ -40,16 +70,57 @@ func (service *RecordCommandService) CreateRecord(ctx context.Context, data type
 }
 
 // GenerateToken generates a jwt token
-func (service *RecordCommandService) GenerateToken(ctx context.Context) (string, error) {
+// The subject owns the records created with the token, a new one is generated unless an admin chooses it.
+// The token is bound to the tenant of the caller, an admin not bound to any can bind it to another one.
+func (service *RecordCommandService) GenerateToken(ctx context.Context, data types.GenerateToken) (string, error) {
+	identity, _ := auth.FromContext(ctx)
+
+	subject := generateID()
+	if len(data.Subject) > 0 {
+		// choosing the subject is impersonating its owner
+		if !identity.IsAdmin() {
+			err := errors.New(apiError.ForbiddenAccess)
+			_ = service.AuditEventCommandServiceInterface.RecordAuditEvent(ctx, auditTypes.NewRecordAuditEvent(auditEntity.ActionTokenGenerate, data.Subject, err))
+
+			return "", err
+		}
+
+		subject = data.Subject
+	}
+
 	// create access token
 	accessTokenClaims := jwt.MapClaims{
 		"iss": "gomora",
+		"sub": subject,
 		"iat": time.Now().Unix(),
 		"exp": time.Now().Add(time.Minute * 15).Unix(),
 	}
 
+	tenantID := identity.TenantID
+	if len(data.TenantID) > 0 {
+		var err error
+		switch {
+		case tenant.Validate(data.TenantID) != nil:
+			err = errors.New(apiError.InvalidPayload)
+		case !identity.IsAdmin() || (len(identity.TenantID) > 0 && identity.TenantID != data.TenantID):
+			err = errors.New(apiError.ForbiddenAccess)
+		}
+		if err != nil {
+			_ = service.AuditEventCommandServiceInterface.RecordAuditEvent(ctx, auditTypes.NewRecordAuditEvent(auditEntity.ActionTokenGenerate, subject, err))
+
+			return "", err
+		}
+
+		tenantID = data.TenantID
+	}
+
+	if len(tenantID) > 0 {
+		accessTokenClaims[tenant.ClaimName] = tenantID
+	}
+
 	at := jwt.NewWithClaims(jwt.SigningMethodHS256, accessTokenClaims)
 	token, err := at.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	_ = service.AuditEventCommandServiceInterface.RecordAuditEvent(ctx, auditTypes.NewRecordAuditEvent(auditEntity.ActionTokenGenerate, subject, err))
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"gomora/infrastructures/database"
+	"gomora/internal/auth"
+	apiError "gomora/internal/errors"
+	"gomora/internal/tenant"
+	auditEntity "gomora/module/audit/domain/entity"
+	auditRepository "gomora/module/audit/infrastructure/repository"
+	auditRepositoryTypes "gomora/module/audit/infrastructure/repository/types"
+	auditService "gomora/module/audit/infrastructure/service"
+	"gomora/module/record/infrastructure/repository"
+	"gomora/module/record/infrastructure/service/types"
+)
+
+func TestCreateRecord(t *testing.T) {
+	auditEvents := &auditRepository.AuditEventMemoryRepository{}
+	records := &repository.RecordMemoryRepository{}
+
+	// the memory storage needs no database
+	service := &RecordCommandService{
+		TransactionHandlerInterface:       database.NoTransactionHandler{},
+		RecordCommandRepositoryInterface:  records,
+		AuditEventCommandServiceInterface: &auditService.AuditEventCommandService{AuditEventCommandRepositoryInterface: auditEvents},
+	}
+
+	ctx := auth.NewContext(context.Background(), auth.Identity{Subject: "alice"})
+	ctx = tenant.NewContext(ctx, "t1")
+
+	record, err := service.CreateRecord(ctx, types.CreateRecord{ID: "1", Data: "data"})
+	if err != nil || record.ID != "1" || record.OwnerID != "alice" || record.TenantID != "t1" {
+		t.Fatalf("unexpected record %+v, %v", record, err)
+	}
+	if selected, err := records.SelectRecordByID(ctx, "1"); err != nil || selected != record {
+		t.Errorf("expected %+v, got %+v, %v", record, selected, err)
+	}
+
+	_, err = service.CreateRecord(ctx, types.CreateRecord{ID: "1", Data: "data"})
+	if err == nil || err.Error() != apiError.DuplicateRecord {
+		t.Errorf("expected %s, got %v", apiError.DuplicateRecord, err)
+	}
+
+	// a record without id gets a generated one
+	record, err = service.CreateRecord(ctx, types.CreateRecord{Data: "data"})
+	if err != nil || len(record.ID) == 0 {
+		t.Errorf("expected a generated id, got %+v, %v", record, err)
+	}
+
+	// every creation is audited, the failed ones included
+	recorded, err := auditEvents.SelectAuditEvents(ctx, auditRepositoryTypes.SelectAuditEvents{Action: auditEntity.ActionRecordCreate, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	outcomes := map[string]int{}
+	for _, auditEvent := range recorded {
+		if auditEvent.Actor != "alice" || auditEvent.TenantID != "t1" {
+			t.Errorf("unexpected audit event %+v", auditEvent)
+		}
+		outcomes[auditEvent.Outcome+auditEvent.ErrorCode]++
+	}
+	if outcomes[auditEntity.OutcomeSuccess] != 2 || outcomes[auditEntity.OutcomeFailure+apiError.DuplicateRecord] != 1 {
+		t.Errorf("expected 2 successes and a duplicate, got %v", outcomes)
+	}
+}
@@ -14,7 +14,7 @@ type RecordQueryService struct {
 
 // GetRecordByID retrieves the record provided by its id
 func (service *RecordQueryService) GetRecordByID(ctx context.Context, ID string) (entity.Record, error) {
-	res, err := service.RecordQueryRepositoryInterface.SelectRecordByID(ID)
+	res, err := service.RecordQueryRepositoryInterface.SelectRecordByID(ctx, ID)
 	if err != nil {
 		return res, err
 	}
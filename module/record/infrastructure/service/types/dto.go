@@ -5,3 +5,9 @@ type CreateRecord struct {
 	ID   string
 	Data string
 }
+
+// GenerateToken service types for generate token
+type GenerateToken struct {
+	Subject  string
+	TenantID string
+}
@@ -32,6 +32,13 @@ type GetRecordResponse struct {
 	CreatedAt int64  `json:"createdAt"`
 }
 
+// GenerateTokenRequest request struct for generate token
+type GenerateTokenRequest struct {
+	Subject  string `json:"subject"`
+	TenantID string `json:"tenantId"`
+}
+
+// GenerateTokenResponse response struct
 type GenerateTokenResponse struct {
 	AccessToken string `json:"accessToken"`
 }
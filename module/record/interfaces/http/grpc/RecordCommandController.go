@@ -27,7 +27,7 @@ func (controller *RecordCommandController) CreateRecord(ctx context.Context, req
 		Data: req.Data,
 	}
 
-	res, err := controller.RecordCommandServiceInterface.CreateRecord(context.TODO(), record)
+	res, err := controller.RecordCommandServiceInterface.CreateRecord(ctx, record)
 	if err != nil {
 		var code codes.Code
 
@@ -36,6 +36,14 @@ func (controller *RecordCommandController) CreateRecord(ctx context.Context, req
 			code = codes.Internal
 		case errors.MissingRecord:
 			code = codes.NotFound
+		case errors.HystrixTimeout:
+			code = codes.Unavailable
+		case errors.RequestTimeout:
+			code = codes.DeadlineExceeded
+		case errors.UnauthorizedAccess:
+			code = codes.Unauthenticated
+		case errors.ForbiddenAccess:
+			code = codes.PermissionDenied
 		default:
 			code = codes.Unknown
 		}
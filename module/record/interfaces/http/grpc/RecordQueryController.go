@@ -5,10 +5,13 @@ import (
 	"fmt"
 
 	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"gomora/internal/errors"
+	"gomora/internal/stale"
 	"gomora/module/record/application"
 	grpcPB "gomora/module/record/interfaces/http/grpc/pb"
 )
@@ -20,7 +23,8 @@ type RecordQueryController struct {
 
 // GetRecordByID retrieves the record id from the proto
 func (controller *RecordQueryController) GetRecordByID(ctx context.Context, req *grpcPB.GetRecordRequest) (*grpcPB.RecordResponse, error) {
-	res, err := controller.RecordQueryServiceInterface.GetRecordByID(context.TODO(), req.Id)
+	ctx, marker := stale.NewContext(ctx)
+	res, err := controller.RecordQueryServiceInterface.GetRecordByID(ctx, req.Id)
 	if err != nil {
 		var code codes.Code
 
@@ -29,6 +33,14 @@ func (controller *RecordQueryController) GetRecordByID(ctx context.Context, req
 			code = codes.Internal
 		case errors.MissingRecord:
 			code = codes.NotFound
+		case errors.HystrixTimeout:
+			code = codes.Unavailable
+		case errors.RequestTimeout:
+			code = codes.DeadlineExceeded
+		case errors.UnauthorizedAccess:
+			code = codes.Unauthenticated
+		case errors.ForbiddenAccess:
+			code = codes.PermissionDenied
 		default:
 			code = codes.Unknown
 		}
@@ -38,6 +50,11 @@ func (controller *RecordQueryController) GetRecordByID(ctx context.Context, req
 		return nil, st.Err()
 	}
 
+	// served from the fallback cache while the database is unavailable
+	if marker.Stale() {
+		_ = grpc.SetHeader(ctx, metadata.Pairs("x-cache", "STALE"))
+	}
+
 	createProtoTime, _ := ptypes.TimestampProto(res.CreatedAt)
 
 	return &grpcPB.RecordResponse{
@@ -1,8 +1,8 @@
 package rest
 
 import (
-	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"time"
 
@@ -69,7 +69,7 @@ func (controller *RecordCommandController) CreateRecord(w http.ResponseWriter, r
 		Data: request.Data,
 	}
 
-	res, err := controller.RecordCommandServiceInterface.CreateRecord(context.TODO(), record)
+	res, err := controller.RecordCommandServiceInterface.CreateRecord(r.Context(), record)
 	if err != nil {
 		var httpCode int
 		var errorMsg string
@@ -81,6 +81,18 @@ func (controller *RecordCommandController) CreateRecord(w http.ResponseWriter, r
 		case errors.DuplicateRecord:
 			httpCode = http.StatusConflict
 			errorMsg = "Record ID already exist."
+		case errors.HystrixTimeout:
+			httpCode = http.StatusServiceUnavailable
+			errorMsg = "Service temporarily unavailable, please try again later."
+		case errors.RequestTimeout:
+			httpCode = http.StatusGatewayTimeout
+			errorMsg = "Request timed out."
+		case errors.UnauthorizedAccess:
+			httpCode = http.StatusUnauthorized
+			errorMsg = "Unauthorized access."
+		case errors.ForbiddenAccess:
+			httpCode = http.StatusForbidden
+			errorMsg = "Forbidden access."
 		default:
 			httpCode = http.StatusInternalServerError
 			errorMsg = "Please contact technical support."
@@ -113,7 +125,25 @@ func (controller *RecordCommandController) CreateRecord(w http.ResponseWriter, r
 
 // GenerateToken request handler to generate token
 func (controller *RecordCommandController) GenerateToken(w http.ResponseWriter, r *http.Request) {
-	token, err := controller.RecordCommandServiceInterface.GenerateToken(context.TODO())
+	var request types.GenerateTokenRequest
+
+	// the request body is optional
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && err != io.EOF {
+		response := viewmodels.HTTPResponseVM{
+			Status:    http.StatusBadRequest,
+			Success:   false,
+			Message:   "Invalid payload request.",
+			ErrorCode: apiError.InvalidRequestPayload,
+		}
+
+		response.JSON(w)
+		return
+	}
+
+	token, err := controller.RecordCommandServiceInterface.GenerateToken(r.Context(), serviceTypes.GenerateToken{
+		Subject:  request.Subject,
+		TenantID: request.TenantID,
+	})
 	if err != nil {
 		var httpCode int
 		var errorMsg string
@@ -122,6 +152,12 @@ func (controller *RecordCommandController) GenerateToken(w http.ResponseWriter,
 		case errors.DatabaseError:
 			httpCode = http.StatusInternalServerError
 			errorMsg = "Error occurred while generating token."
+		case errors.InvalidPayload:
+			httpCode = http.StatusBadRequest
+			errorMsg = "Invalid tenant ID."
+		case errors.ForbiddenAccess:
+			httpCode = http.StatusForbidden
+			errorMsg = "Only an admin can choose the subject or the tenant."
 		default:
 			httpCode = http.StatusInternalServerError
 			errorMsg = "Please contact technical support."
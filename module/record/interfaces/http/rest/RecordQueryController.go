@@ -1,13 +1,13 @@
 package rest
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 
 	"gomora/interfaces/http/rest/viewmodels"
 	"gomora/internal/errors"
+	"gomora/internal/stale"
 	"gomora/module/record/application"
 	types "gomora/module/record/interfaces/http"
 )
@@ -17,7 +17,7 @@ type RecordQueryController struct {
 	application.RecordQueryServiceInterface
 }
 
-// GetRecordByID retrieves the tenant id from the rest request
+// GetRecordByID retrieves the record of the request tenant from the rest request
 func (controller *RecordQueryController) GetRecordByID(w http.ResponseWriter, r *http.Request) {
 	recordID := chi.URLParam(r, "id")
 
@@ -33,7 +33,8 @@ func (controller *RecordQueryController) GetRecordByID(w http.ResponseWriter, r
 		return
 	}
 
-	res, err := controller.RecordQueryServiceInterface.GetRecordByID(context.TODO(), recordID)
+	ctx, marker := stale.NewContext(r.Context())
+	res, err := controller.RecordQueryServiceInterface.GetRecordByID(ctx, recordID)
 	if err != nil {
 		var httpCode int
 		var errorMsg string
@@ -45,6 +46,18 @@ func (controller *RecordQueryController) GetRecordByID(w http.ResponseWriter, r
 		case errors.MissingRecord:
 			httpCode = http.StatusNotFound
 			errorMsg = "No record found."
+		case errors.HystrixTimeout:
+			httpCode = http.StatusServiceUnavailable
+			errorMsg = "Service temporarily unavailable, please try again later."
+		case errors.RequestTimeout:
+			httpCode = http.StatusGatewayTimeout
+			errorMsg = "Request timed out."
+		case errors.UnauthorizedAccess:
+			httpCode = http.StatusUnauthorized
+			errorMsg = "Unauthorized access."
+		case errors.ForbiddenAccess:
+			httpCode = http.StatusForbidden
+			errorMsg = "Forbidden access."
 		default:
 			httpCode = http.StatusInternalServerError
 			errorMsg = "Please contact technical support."
@@ -61,6 +74,12 @@ func (controller *RecordQueryController) GetRecordByID(w http.ResponseWriter, r
 		return
 	}
 
+	// served from the fallback cache while the database is unavailable
+	if marker.Stale() {
+		w.Header().Set("Warning", `110 - "Response is Stale"`)
+		w.Header().Set("X-Cache", "STALE")
+	}
+
 	response := viewmodels.HTTPResponseVM{
 		Status:  http.StatusOK,
 		Success: true,